@@ -0,0 +1,148 @@
+package adminguard
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/audit"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/ipfilter"
+	"github.com/playnet-public/gorcon-arma/webhook"
+)
+
+//Cfg contains all data required by the Guard
+type Cfg struct {
+	Trusted   []string
+	MaxLogins int
+	Window    time.Duration
+}
+
+//Config is the Interface providing Configs for the Guard
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Guard raises alerts for RCon admin logins from untrusted addresses and unusual rates of successful logins
+//BattlEye sends no message for failed logins, so password guessing itself cannot be detected
+type Guard struct {
+	trusted   []*net.IPNet
+	maxLogins int
+	window    time.Duration
+	audit     *audit.Log
+	hooks     *webhook.Sender
+	console   io.Writer
+
+	logins struct {
+		sync.Mutex
+		all  []time.Time
+		byIP map[string][]time.Time
+	}
+}
+
+//New creates a Guard with given Config, alerts are written to console if not nil
+func New(c Config, auditLog *audit.Log, hooks *webhook.Sender, console io.Writer) (*Guard, error) {
+	cfg := c.GetConfig()
+	if cfg.MaxLogins == 0 {
+		cfg.MaxLogins = 5
+	}
+	if cfg.Window == 0 {
+		cfg.Window = time.Minute * 10
+	}
+	g := &Guard{
+		maxLogins: cfg.MaxLogins,
+		window:    cfg.Window,
+		audit:     auditLog,
+		hooks:     hooks,
+		console:   console,
+	}
+	g.logins.byIP = make(map[string][]time.Time)
+	for _, text := range cfg.Trusted {
+		n, err := ipfilter.ParseNet(text)
+		if err != nil {
+			return nil, err
+		}
+		g.trusted = append(g.trusted, n)
+	}
+	return g, nil
+}
+
+//HandleAdminLogin checks the source of the successful login and the login rate
+func (g *Guard) HandleAdminLogin(e events.AdminLogin) {
+	g.check(e, time.Now())
+}
+
+func (g *Guard) check(e events.AdminLogin, now time.Time) {
+	addr := net.JoinHostPort(e.IP, e.Port)
+	if !g.isTrusted(e.IP) {
+		g.alert(fmt.Sprintf("RCon admin #%d logged in from untrusted address %s", e.Number, addr), e.IP, "untrusted")
+	} else {
+		glog.V(1).Infof("RCon admin #%d logged in from trusted address %s", e.Number, addr)
+	}
+	all, fromIP := g.count(e.IP, now)
+	if fromIP == g.maxLogins+1 {
+		g.alert(fmt.Sprintf("%d successful RCon logins from %s within %v, the password may be leaked", fromIP, e.IP, g.window), e.IP, "rate")
+	}
+	if all == g.maxLogins*2+1 {
+		g.alert(fmt.Sprintf("%d successful RCon logins within %v, the password may be leaked", all, g.window), e.IP, "rate")
+	}
+}
+
+//count records a login and returns the amount of logins within the window overall and from ip
+//The counts only increase until the window passed, so every burst alerts once
+func (g *Guard) count(ip string, now time.Time) (int, int) {
+	g.logins.Lock()
+	defer g.logins.Unlock()
+	since := now.Add(-g.window)
+	g.logins.all = append(prune(g.logins.all, since), now)
+	for key, list := range g.logins.byIP {
+		if list = prune(list, since); len(list) == 0 {
+			delete(g.logins.byIP, key)
+		} else {
+			g.logins.byIP[key] = list
+		}
+	}
+	g.logins.byIP[ip] = append(g.logins.byIP[ip], now)
+	return len(g.logins.all), len(g.logins.byIP[ip])
+}
+
+func prune(list []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(list) && !list[i].After(since) {
+		i++
+	}
+	return list[i:]
+}
+
+func (g *Guard) isTrusted(ip string) bool {
+	addr := net.ParseIP(ip)
+	for _, n := range g.trusted {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+//alert the event stream, the webhooks and the audit trail
+func (g *Guard) alert(text, ip, reason string) {
+	glog.Warningln("Alert:", text)
+	if g.console != nil {
+		fmt.Fprintln(g.console, "Alert:", text)
+	}
+	g.hooks.Send("[gorcon-arma] Alert: " + text)
+	g.audit.Record(audit.Entry{
+		Source: "adminguard",
+		Action: "alert",
+		IP:     ip,
+		Reason: reason,
+	})
+}
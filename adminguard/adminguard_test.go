@@ -0,0 +1,48 @@
+package adminguard
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/events"
+)
+
+func Test_Check(t *testing.T) {
+	console := &bytes.Buffer{}
+	g, err := New(Cfg{Trusted: []string{"127.0.0.1", "10.0.0.0/8"}, MaxLogins: 2, Window: time.Minute}, nil, nil, console)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	g.check(events.AdminLogin{Number: 0, IP: "10.1.2.3", Port: "4000"}, now)
+	if console.Len() != 0 {
+		t.Error("Expected no Alert for trusted Login Got:", console.String())
+	}
+	g.check(events.AdminLogin{Number: 1, IP: "1.2.3.4", Port: "4000"}, now)
+	if console.String() != "Alert: RCon admin #1 logged in from untrusted address 1.2.3.4:4000\n" {
+		t.Error("Unexpected Alert:", console.String())
+	}
+
+	console.Reset()
+	for i := 0; i < 4; i++ {
+		g.check(events.AdminLogin{Number: i, IP: "127.0.0.1", Port: "4000"}, now.Add(time.Second*time.Duration(i)))
+	}
+	expected := "Alert: 3 successful RCon logins from 127.0.0.1 within 1m0s, the password may be leaked\n" +
+		"Alert: 5 successful RCon logins within 1m0s, the password may be leaked\n"
+	if console.String() != expected {
+		t.Error("Expected:", expected, "Got:", console.String())
+	}
+
+	console.Reset()
+	g.check(events.AdminLogin{Number: 9, IP: "127.0.0.1", Port: "4000"}, now.Add(time.Minute*2))
+	if console.Len() != 0 {
+		t.Error("Expected Counts to reset after Window Got:", console.String())
+	}
+}
+
+func Test_New(t *testing.T) {
+	if _, err := New(Cfg{Trusted: []string{"not an ip"}}, nil, nil, nil); err == nil {
+		t.Error("Expected Error for invalid trusted Address")
+	}
+}
@@ -0,0 +1,159 @@
+package announcer
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/welcome"
+)
+
+//Cfg contains all data required by the Announcer
+type Cfg struct {
+	Messages   []string
+	File       string
+	Interval   time.Duration
+	Random     bool
+	MinPlayers int
+}
+
+//Config is the Interface providing Configs for the Announcer
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to broadcast the messages
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Uptime provides the start time of the server
+type Uptime interface {
+	Started() time.Time
+}
+
+//Announcer broadcasts a rotating list of messages
+type Announcer struct {
+	client     Client
+	roster     *players.Roster
+	restarts   welcome.Restarts
+	uptime     Uptime
+	messages   []string
+	file       string
+	interval   time.Duration
+	random     bool
+	minPlayers int
+
+	order []int
+	pos   int
+}
+
+//New creates an Announcer with given Config, restarts and uptime may be nil if unknown
+func New(c Config, client Client, roster *players.Roster, restarts welcome.Restarts, uptime Uptime) *Announcer {
+	cfg := c.GetConfig()
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Minute * 10
+	}
+	return &Announcer{
+		client:     client,
+		roster:     roster,
+		restarts:   restarts,
+		uptime:     uptime,
+		messages:   cfg.Messages,
+		file:       cfg.File,
+		interval:   cfg.Interval,
+		random:     cfg.Random,
+		minPlayers: cfg.MinPlayers,
+	}
+}
+
+//Run broadcasts the next message every interval
+func (a *Announcer) Run() {
+	for {
+		glog.V(10).Infoln("Looping in Announcer")
+		time.Sleep(a.interval)
+		a.announce()
+	}
+}
+
+//announce broadcasts the next message unless there are not enough players online
+func (a *Announcer) announce() {
+	if count := a.roster.Count(); count < a.minPlayers {
+		glog.V(3).Infof("Skipping Announcement for %d Players", count)
+		return
+	}
+	messages := a.load()
+	text, ok := a.next(messages)
+	if !ok {
+		return
+	}
+	uptime := "unknown"
+	if a.uptime != nil {
+		if started := a.uptime.Started(); !started.IsZero() {
+			uptime = message.Duration(time.Since(started))
+		}
+	}
+	text = message.Render(text, message.Vars{
+		"players": a.roster.Count(),
+		"restart": welcome.NextRestart(a.restarts),
+		"uptime":  uptime,
+		"time":    time.Now().Format("15:04"),
+	})
+	glog.V(2).Infoln("Announcing:", text)
+	a.client.RunCommand("say -1 "+text, nil)
+}
+
+//load returns the configured messages followed by those in the file
+//The file is read on every announcement so it can be edited while running
+func (a *Announcer) load() []string {
+	messages := a.messages
+	if a.file == "" {
+		return messages
+	}
+	content, err := ioutil.ReadFile(a.file)
+	if err != nil {
+		glog.Errorln("Could not read Announcements:", err)
+		return messages
+	}
+	messages = append([]string(nil), messages...)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			messages = append(messages, line)
+		}
+	}
+	return messages
+}
+
+//next returns the next message in order, random order is shuffled once per round
+func (a *Announcer) next(messages []string) (string, bool) {
+	if len(messages) == 0 {
+		return "", false
+	}
+	if a.pos >= len(a.order) || len(a.order) != len(messages) {
+		a.pos = 0
+		a.order = rand.Perm(len(messages))
+		if !a.random {
+			for i := range a.order {
+				a.order[i] = i
+			}
+		}
+	}
+	text := messages[a.order[a.pos]]
+	a.pos++
+	return text, true
+}
+
@@ -0,0 +1,89 @@
+package announcer
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+type fakeServer time.Time
+
+func (f fakeServer) NextRestart() time.Time {
+	return time.Time(f)
+}
+
+func (f fakeServer) Started() time.Time {
+	return time.Time(f)
+}
+
+func Test_Announce(t *testing.T) {
+	client := &fakeClient{}
+	roster := players.NewRoster()
+	server := fakeServer(time.Now().Add(-(time.Hour + time.Minute*5 + time.Second)))
+	a := New(Cfg{
+		Messages:   []string{"{players} players online", "Up for {uptime}", "Restart in {restart}"},
+		MinPlayers: 1,
+	}, client, roster, nil, server)
+
+	a.announce()
+	if len(client.commands) != 0 {
+		t.Error("Expected no Announcement on empty Server Got:", client.commands)
+	}
+	roster.Add(players.Player{Number: 1, Name: "Steve"})
+	for i := 0; i < 4; i++ {
+		a.announce()
+	}
+	expected := []string{
+		"say -1 1 players online",
+		"say -1 Up for 1h 5m",
+		"say -1 Restart in unknown",
+		"say -1 1 players online",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_RandomRound(t *testing.T) {
+	a := New(Cfg{Random: true}, nil, nil, nil, nil)
+	messages := []string{"a", "b", "c", "d"}
+	var round []string
+	for range messages {
+		text, _ := a.next(messages)
+		round = append(round, text)
+	}
+	sort.Strings(round)
+	if !reflect.DeepEqual(round, messages) {
+		t.Error("Expected every Message once per Round Got:", round)
+	}
+}
+
+func Test_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "announcer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "announcements.txt")
+	ioutil.WriteFile(file, []byte("# comment\nVisit our website\n\nJoin our Discord\n"), 0644)
+	a := New(Cfg{Messages: []string{"Hello"}, File: file}, nil, nil, nil, nil)
+	expected := []string{"Hello", "Visit our website", "Join our Discord"}
+	if res := a.load(); !reflect.DeepEqual(res, expected) {
+		t.Error("Expected:", expected, "Got:", res)
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+//Cfg contains all data required by the Server
+type Cfg struct {
+	Listen string
+	Token  string
+}
+
+//Config is the Interface providing Configs for the Server
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Error is returned by handlers to answer with a specific status code
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+//BadRequest returns an Error with status 400
+func BadRequest(message string) error {
+	return Error{Status: http.StatusBadRequest, Message: message}
+}
+
+//HandlerFunc handles an API request, the result is sent to the client as json
+type HandlerFunc func(r *http.Request) (interface{}, error)
+
+//Server is the HTTP API used by external tools to control gorcon-arma
+type Server struct {
+	mux    *http.ServeMux
+	listen string
+	token  string
+}
+
+//New creates a Server with given Config
+func New(c Config) *Server {
+	cfg := c.GetConfig()
+	if cfg.Listen == "" {
+		cfg.Listen = "127.0.0.1:8080"
+	}
+	return &Server{
+		mux:    http.NewServeMux(),
+		listen: cfg.Listen,
+		token:  cfg.Token,
+	}
+}
+
+//Handle registers fn for pattern and given methods (all methods if empty)
+func (s *Server) Handle(pattern string, fn HandlerFunc, methods ...string) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if !allowed(r.Method, methods) {
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+			writeJSON(w, http.StatusMethodNotAllowed, errorBody("method not allowed"))
+			return
+		}
+		result, err := fn(r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if e, ok := err.(Error); ok {
+				status = e.Status
+			}
+			glog.V(2).Infof("API %v %v failed: %v", r.Method, r.URL.Path, err)
+			writeJSON(w, status, errorBody(err.Error()))
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+//ServeHTTP checks the token and passes the request to the registered handlers
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, errorBody("unauthorized"))
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+//ListenAndServe blocks serving the API
+func (s *Server) ListenAndServe() error {
+	glog.Infoln("API listening on", s.listen)
+	return http.ListenAndServe(s.listen, s)
+}
+
+func allowed(method string, methods []string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func errorBody(message string) interface{} {
+	return map[string]string{"error": message}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		glog.Errorln("Could not write API response:", err)
+	}
+}
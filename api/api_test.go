@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer() *Server {
+	s := New(Cfg{Token: "secret"})
+	s.Handle("/echo", func(r *http.Request) (interface{}, error) {
+		text := r.FormValue("text")
+		if text == "" {
+			return nil, BadRequest("text missing")
+		}
+		return map[string]string{"text": text}, nil
+	}, "POST")
+	return s
+}
+
+func Test_Handle(t *testing.T) {
+	s := newServer()
+	tests := []struct {
+		method, token, body string
+		status              int
+		response            string
+	}{
+		{"POST", "secret", "text=hi", http.StatusOK, `{"text":"hi"}`},
+		{"POST", "secret", "", http.StatusBadRequest, `{"error":"text missing"}`},
+		{"GET", "secret", "", http.StatusMethodNotAllowed, `{"error":"method not allowed"}`},
+		{"POST", "wrong", "text=hi", http.StatusUnauthorized, `{"error":"unauthorized"}`},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/echo", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "Bearer "+test.token)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != test.status || strings.TrimSpace(rec.Body.String()) != test.response {
+			t.Errorf("%v %v: Expected: %v %v Got: %v %v", test.method, test.body, test.status, test.response, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+//Entry of the audit trail
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Action string    `json:"action"`
+	Name   string    `json:"name,omitempty"`
+	GUID   string    `json:"guid,omitempty"`
+	IP     string    `json:"ip,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+//Log appends entries as json lines to a file
+type Log struct {
+	sync.Mutex
+	path string
+}
+
+//New creates a Log writing to path
+func New(path string) *Log {
+	return &Log{path: path}
+}
+
+//Record appends e to the audit trail, a nil Log discards all entries
+func (l *Log) Record(e Entry) {
+	if l == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		glog.Errorln("Could not encode Audit Entry:", err)
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		glog.Errorln("Could not open Audit Log:", err)
+		return
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		glog.Errorln("Could not write Audit Log:", err)
+	}
+}
@@ -0,0 +1,54 @@
+package beguid
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//ErrInvalidID is returned for strings being neither a GUID nor a SteamID64
+var ErrInvalidID = errors.New("Neither a BattlEye GUID nor a SteamID64")
+
+var guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+
+//steamIDBase is the lowest SteamID64 of an individual account
+const steamIDBase = 76561197960265728
+
+//FromSteamID computes the BattlEye GUID of a SteamID64 (md5 of "BE" followed by the little endian id)
+func FromSteamID(steamID uint64) string {
+	data := make([]byte, 10)
+	copy(data, "BE")
+	binary.LittleEndian.PutUint64(data[2:], steamID)
+	return fmt.Sprintf("%x", md5.Sum(data))
+}
+
+//IsGUID returns whether s is a BattlEye GUID
+func IsGUID(s string) bool {
+	return guidPattern.MatchString(s)
+}
+
+//ParseSteamID parses s as SteamID64 of an individual account
+func ParseSteamID(s string) (uint64, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id < steamIDBase {
+		return 0, ErrInvalidID
+	}
+	return id, nil
+}
+
+//Resolve accepts a GUID or SteamID64 and returns the lowercase GUID and the SteamID64 if given
+func Resolve(id string) (guid, steamID string, err error) {
+	id = strings.TrimSpace(id)
+	if IsGUID(id) {
+		return strings.ToLower(id), "", nil
+	}
+	sid, err := ParseSteamID(id)
+	if err != nil {
+		return "", "", err
+	}
+	return FromSteamID(sid), id, nil
+}
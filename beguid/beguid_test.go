@@ -0,0 +1,38 @@
+package beguid
+
+import "testing"
+
+func Test_FromSteamID(t *testing.T) {
+	var tests = []struct {
+		steamID  uint64
+		expected string
+	}{
+		{76561197960287930, "a357f31c8335a5263e0d816e64445b6a"},
+		{76561198000000000, "edc48a4a45cdc3e925dc160020c42595"},
+	}
+	for _, v := range tests {
+		if res := FromSteamID(v.steamID); res != v.expected {
+			t.Error("Expected:", v.expected, "Got:", res)
+		}
+	}
+}
+
+func Test_Resolve(t *testing.T) {
+	var tests = []struct {
+		id      string
+		guid    string
+		steamID string
+		err     error
+	}{
+		{"A357F31C8335A5263E0D816E64445B6A", "a357f31c8335a5263e0d816e64445b6a", "", nil},
+		{" 76561197960287930 ", "a357f31c8335a5263e0d816e64445b6a", "76561197960287930", nil},
+		{"12345", "", "", ErrInvalidID},
+		{"steve", "", "", ErrInvalidID},
+	}
+	for _, v := range tests {
+		guid, steamID, err := Resolve(v.id)
+		if guid != v.guid || steamID != v.steamID || err != v.err {
+			t.Errorf("%q Expected: %v %v %v Got: %v %v %v", v.id, v.guid, v.steamID, v.err, guid, steamID, err)
+		}
+	}
+}
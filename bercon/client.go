@@ -1,6 +1,7 @@
 package bercon
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net"
@@ -161,7 +162,26 @@ func (c *Client) RunCommand(cmd string, w io.WriteCloser) {
 	c.cmdChan <- transmission{command: []byte(cmd), writeCloser: w}
 }
 
-func (c *Client) handleResponse(seq byte, response []byte, last bool) {
+//Query runs cmd and waits until the full response arrived or timeout is reached
+func (c *Client) Query(cmd string, timeout time.Duration) (string, error) {
+	r := &queryResponse{done: make(chan struct{})}
+	deadline := time.After(timeout)
+	select {
+	case c.cmdChan <- transmission{command: []byte(cmd), writeCloser: r}:
+	case <-deadline:
+		return "", ErrTimeout
+	}
+	select {
+	case <-r.done:
+		return r.buffer.String(), nil
+	case <-deadline:
+		return "", ErrTimeout
+	}
+}
+
+//handleResponse collects the fragment index of count of the response to seq
+//The response is written once all fragments arrived, in order of their index
+func (c *Client) handleResponse(seq byte, response []byte, index, count byte) {
 	c.cmdLock.RLock()
 	trm, ex := c.cmdMap[seq]
 	c.cmdLock.RUnlock()
@@ -178,24 +198,31 @@ func (c *Client) handleResponse(seq byte, response []byte, last bool) {
 			glog.Warningf("No Entry in cmdMap for: %v - (%v)", string(response), response)
 		}
 	} else {
-		trail := []byte("\n")
-		if !last {
-			trail = []byte{}
+		if len(trm.fragments) != int(count) {
+			trm.fragments = make([][]byte, count)
+			trm.received = 0
 		}
-		trm.response = append(trm.response, response...)
-		trm.response = append(trm.response, trail...)
-		if last {
-			if trm.writeCloser != nil {
-				trm.writeCloser.Write(trm.response)
-				trm.writeCloser.Close()
-			}
-
-			//TODO: Evaluate if this is required
-			go func(c *Client, seq byte) {
-				c.cmdLock.Lock()
-				delete(c.cmdMap, seq)
-				c.cmdLock.Unlock()
-			}(c, seq)
+		if trm.fragments[index] == nil {
+			trm.fragments[index] = append([]byte{}, response...)
+			trm.received++
+		}
+		if trm.received < len(trm.fragments) {
+			c.cmdLock.Lock()
+			c.cmdMap[seq] = trm
+			c.cmdLock.Unlock()
+			return
 		}
+		trm.response = append(bytes.Join(trm.fragments, nil), '\n')
+		if trm.writeCloser != nil {
+			trm.writeCloser.Write(trm.response)
+			trm.writeCloser.Close()
+		}
+
+		//TODO: Evaluate if this is required
+		go func(c *Client, seq byte) {
+			c.cmdLock.Lock()
+			delete(c.cmdMap, seq)
+			c.cmdLock.Unlock()
+		}(c, seq)
 	}
 }
@@ -1 +1,69 @@
 package bercon
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Query(t *testing.T) {
+	c := New(Config{})
+	go func() {
+		trm := <-c.cmdChan
+		if string(trm.command) != "players" {
+			t.Error("Expected: players Got:", string(trm.command))
+		}
+		trm.writeCloser.Write([]byte("Players on server:\n"))
+		trm.writeCloser.Close()
+	}()
+	res, err := c.Query("players", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "Players on server:\n" {
+		t.Error("Unexpected Response:", res)
+	}
+}
+
+func Test_QueryTimeout(t *testing.T) {
+	c := New(Config{})
+	_, err := c.Query("players", time.Millisecond*10)
+	if err != ErrTimeout {
+		t.Error("Expected:", ErrTimeout, "Got:", err)
+	}
+}
+
+func Test_MultiPacketResponse(t *testing.T) {
+	c := New(Config{})
+	r := &queryResponse{done: make(chan struct{})}
+	c.cmdMap[7] = transmission{command: []byte("players"), writeCloser: r}
+	fragments := []string{"Players on server:\n0 Steve", "\n1 Kevin", "\n(2 players in total)"}
+	//fragments may arrive in any order
+	for _, i := range []int{1, 0, 2} {
+		data := append([]byte{7, 0x00, byte(len(fragments)), byte(i)}, fragments[i]...)
+		if err := c.handlePacket(buildPacket(data, packetType.Command)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-r.done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Response to be complete")
+	}
+	expected := "Players on server:\n0 Steve\n1 Kevin\n(2 players in total)\n"
+	if res := r.buffer.String(); res != expected {
+		t.Errorf("Expected: %q Got: %q", expected, res)
+	}
+}
+
+func Test_SinglePacketResponse(t *testing.T) {
+	c := New(Config{})
+	r := &queryResponse{done: make(chan struct{})}
+	c.cmdMap[3] = transmission{command: []byte("bans"), writeCloser: r}
+	if err := c.handlePacket(buildPacket(append([]byte{3}, "GUID Bans:"...), packetType.Command)); err != nil {
+		t.Fatal(err)
+	}
+	<-r.done
+	if res := r.buffer.String(); res != "GUID Bans:\n" {
+		t.Errorf("Unexpected Response: %q", res)
+	}
+}
@@ -57,12 +57,14 @@ func verifyLogin(packet []byte) (byte, error) {
 	return packet[8], nil
 }
 
+//checkMultiPacketResponse returns the packet count and index of a command response without header
+//The multi packet header follows the sequence: 0x00, count, index
 func checkMultiPacketResponse(data []byte) (byte, byte, bool) {
-	if len(data) < 3 {
+	if len(data) < 6 {
 		return 0, 0, false
 	}
-	if data[0] != 0x01 || data[2] != 0x00 {
+	if data[1] != packetType.Command || data[3] != 0x00 || data[5] >= data[4] {
 		return 0, 0, false
 	}
-	return data[3], data[4], true
+	return data[4], data[5], true
 }
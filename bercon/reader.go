@@ -75,24 +75,22 @@ func (c *Client) handlePacket(packet []byte) error {
 	packetCount, currentPacket, isMultiPacket := checkMultiPacketResponse(data)
 	glog.V(3).Infof("Packet: %v - Sequence: %v - IsMulti: %v", string(data), seq, isMultiPacket)
 	if !isMultiPacket {
-		c.handleResponse(seq, data[3:], true)
+		c.handleResponse(seq, data[3:], 0, 1)
 		return nil
 	}
-
-	if currentPacket+1 < packetCount {
-		c.handleResponse(seq, data[6:], false)
-	} else {
-		c.handleResponse(seq, data[6:], true)
-	}
-
+	c.handleResponse(seq, data[6:], currentPacket, packetCount)
 	return nil
 }
 
 func (c *Client) handleServerMessage(data []byte) {
 	var ChatPatterns = []string{
 		/*"RCon admin", <- Kicked out to handle as event? */
+		"(Global)",
+		"(Side)",
+		"(Command)",
 		"(Group)",
 		"(Vehicle)",
+		"(Direct)",
 		"(Unknown)",
 	}
 	for _, v := range ChatPatterns {
@@ -109,19 +107,14 @@ func (c *Client) handleServerMessage(data []byte) {
 			return
 		}
 	}
+	if strings.HasPrefix(string(data), "RCon admin") {
+		glog.Infoln("Login Event:", strings.TrimSpace(string(data)))
+	}
 	if c.eventWriter.Writer != nil {
 		c.eventWriter.Lock()
-		if strings.Contains(string(data), "logged in") {
-			glog.V(2).Infoln("Login Event: ", string(data))
-			_, err := c.eventWriter.Write(data)
-			if err != nil {
-				glog.Error(err)
-			}
-		} else {
-			_, err := c.eventWriter.Write(data)
-			if err != nil {
-				glog.Error(err)
-			}
+		_, err := c.eventWriter.Write(data)
+		if err != nil {
+			glog.Error(err)
 		}
 		c.eventWriter.Unlock()
 	}
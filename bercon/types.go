@@ -1,6 +1,7 @@
 package bercon
 
 import (
+	"bytes"
 	"io"
 	"net"
 	"sync"
@@ -30,10 +31,27 @@ type transmission struct {
 	command     []byte
 	sequence    byte
 	response    []byte
+	fragments   [][]byte
+	received    int
 	timestamp   time.Time
 	writeCloser io.WriteCloser
 }
 
+//queryResponse collects a command response for Query
+type queryResponse struct {
+	buffer bytes.Buffer
+	done   chan struct{}
+}
+
+func (r *queryResponse) Write(p []byte) (int, error) {
+	return r.buffer.Write(p)
+}
+
+func (r *queryResponse) Close() error {
+	close(r.done)
+	return nil
+}
+
 //Client is the the Object Handling the Connection
 type Client struct {
 
@@ -0,0 +1,131 @@
+package chatcmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/playnet-public/gorcon-arma/beguid"
+)
+
+func (r *Router) registerBuiltins() {
+	builtins := []Command{
+		{
+			Name:    "help",
+			Usage:   "[command]",
+			Help:    "Lists all commands or shows help for one",
+			Handler: r.help,
+		},
+		{
+			Name:    "kick",
+			Usage:   "<name> [reason]",
+			Help:    "Kicks a player",
+			Admin:   true,
+			Handler: r.kick,
+		},
+		{
+			Name:    "ban",
+			Usage:   "<name> <minutes> [reason]",
+			Help:    "Bans a player (0 minutes = permanent)",
+			Admin:   true,
+			Handler: r.ban,
+		},
+		{
+			Name:    "addban",
+			Usage:   "<guid|steamid64> <minutes> [reason]",
+			Help:    "Bans a GUID or SteamID64 (0 minutes = permanent)",
+			Admin:   true,
+			Handler: r.addBan,
+		},
+		{
+			Name:    "say",
+			Usage:   "<text>",
+			Help:    "Sends a global message",
+			Admin:   true,
+			Handler: r.say,
+		},
+		{
+			Name:    "lock",
+			Help:    "Locks the server",
+			Admin:   true,
+			Handler: r.raw("#lock", "Server locked"),
+		},
+		{
+			Name:    "unlock",
+			Help:    "Unlocks the server",
+			Admin:   true,
+			Handler: r.raw("#unlock", "Server unlocked"),
+		},
+	}
+	for _, cmd := range builtins {
+		r.Register(cmd)
+	}
+}
+
+func (r *Router) kick(ctx Context) (string, error) {
+	p, rest, err := r.FindPlayer(ctx.Args)
+	if err != nil {
+		return "", err
+	}
+	reason := strings.Join(rest, " ")
+	if reason == "" {
+		reason = "Kicked by " + ctx.Player.Name
+	}
+	r.client.RunCommand(fmt.Sprintf("kick %d %s", p.Number, reason), nil)
+	return fmt.Sprintf("Kicked %s", p.Name), nil
+}
+
+func (r *Router) ban(ctx Context) (string, error) {
+	p, rest, err := r.FindPlayer(ctx.Args)
+	if err != nil {
+		return "", err
+	}
+	if len(rest) == 0 {
+		return "", ErrUsage
+	}
+	minutes, err := strconv.Atoi(rest[0])
+	if err != nil || minutes < 0 {
+		return "", ErrUsage
+	}
+	reason := strings.Join(rest[1:], " ")
+	if reason == "" {
+		reason = "Banned by " + ctx.Player.Name
+	}
+	r.client.RunCommand(fmt.Sprintf("ban %d %d %s", p.Number, minutes, reason), nil)
+	return fmt.Sprintf("Banned %s for %d minutes", p.Name, minutes), nil
+}
+
+func (r *Router) addBan(ctx Context) (string, error) {
+	if len(ctx.Args) < 2 {
+		return "", ErrUsage
+	}
+	guid, _, err := beguid.Resolve(ctx.Args[0])
+	if err != nil {
+		return "", err
+	}
+	minutes, err := strconv.Atoi(ctx.Args[1])
+	if err != nil || minutes < 0 {
+		return "", ErrUsage
+	}
+	reason := strings.Join(ctx.Args[2:], " ")
+	if reason == "" {
+		reason = "Banned by " + ctx.Player.Name
+	}
+	r.client.RunCommand(fmt.Sprintf("addBan %s %d %s", guid, minutes, reason), nil)
+	return fmt.Sprintf("Banned %s for %d minutes", guid, minutes), nil
+}
+
+func (r *Router) say(ctx Context) (string, error) {
+	if len(ctx.Args) == 0 {
+		return "", ErrUsage
+	}
+	r.client.RunCommand("say -1 "+strings.Join(ctx.Args, " "), nil)
+	return "", nil
+}
+
+func (r *Router) raw(cmd, answer string) Handler {
+	return func(ctx Context) (string, error) {
+		r.client.RunCommand(cmd, nil)
+		return answer, nil
+	}
+}
@@ -0,0 +1,264 @@
+package chatcmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+var (
+	//ErrUsage is returned by handlers called with invalid arguments
+	ErrUsage = errors.New("Invalid Usage")
+	//ErrPlayerNotFound is returned if no player matches the given name
+	ErrPlayerNotFound = errors.New("Player not found")
+)
+
+//Context of a chat command call
+type Context struct {
+	Player  players.Player
+	Channel string
+	Args    []string
+	Admin   bool
+}
+
+//Handler executes a command and returns the answer sent to the calling player
+type Handler func(ctx Context) (string, error)
+
+//Command callable from the in-game chat
+type Command struct {
+	Name     string
+	Usage    string
+	Help     string
+	Admin    bool
+	Cooldown time.Duration
+	Handler  Handler
+}
+
+//Static is a declarative command answering with a fixed message
+type Static struct {
+	Name     string
+	Help     string
+	Response string
+	Admin    bool
+	Cooldown int
+}
+
+//Cfg contains all data required by the Router
+type Cfg struct {
+	Prefix   string
+	Admins   []string
+	Commands []Static
+}
+
+//Config is the Interface providing Configs for the Router
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to answer and execute commands
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Router dispatches chat messages starting with the prefix to the registered commands
+type Router struct {
+	client Client
+	roster *players.Roster
+	prefix string
+	admins map[string]bool
+
+	commands struct {
+		sync.RWMutex
+		m map[string]Command
+	}
+
+	cooldowns struct {
+		sync.Mutex
+		m map[string]time.Time
+	}
+}
+
+//New creates a Router with the builtin and configured commands
+func New(c Config, client Client, roster *players.Roster) (*Router, error) {
+	cfg := c.GetConfig()
+	if cfg.Prefix == "" {
+		cfg.Prefix = "!"
+	}
+	r := &Router{
+		client: client,
+		roster: roster,
+		prefix: cfg.Prefix,
+		admins: make(map[string]bool),
+	}
+	r.commands.m = make(map[string]Command)
+	r.cooldowns.m = make(map[string]time.Time)
+	for _, guid := range cfg.Admins {
+		r.admins[strings.ToLower(guid)] = true
+	}
+	r.registerBuiltins()
+	for _, s := range cfg.Commands {
+		if err := r.Register(s.command()); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+//Register a command, existing commands with the same name are replaced
+func (r *Router) Register(cmd Command) error {
+	if cmd.Name == "" || cmd.Handler == nil {
+		return fmt.Errorf("chat command %q requires name and handler", cmd.Name)
+	}
+	r.commands.Lock()
+	r.commands.m[strings.ToLower(cmd.Name)] = cmd
+	r.commands.Unlock()
+	return nil
+}
+
+//IsAdmin returns whether the guid belongs to an admin
+//Chat commands are only granted admin rights if the GUID of the player was verified by BattlEye
+func (r *Router) IsAdmin(guid string) bool {
+	return guid != "" && r.admins[strings.ToLower(guid)]
+}
+
+//HandleChat runs the command contained in a chat message
+func (r *Router) HandleChat(e events.Chat) {
+	if !strings.Contains(e.Message, ": "+r.prefix) {
+		return
+	}
+	p, text, err := r.roster.Sender(e.Message)
+	if err != nil {
+		glog.V(2).Infof("ChatCmd could not attribute %q: %v", e.Message, err)
+		return
+	}
+	if !strings.HasPrefix(text, r.prefix) {
+		return
+	}
+	fields := strings.Fields(strings.TrimPrefix(text, r.prefix))
+	if len(fields) == 0 {
+		return
+	}
+	r.commands.RLock()
+	cmd, ok := r.commands.m[strings.ToLower(fields[0])]
+	r.commands.RUnlock()
+	if !ok {
+		return
+	}
+	ctx := Context{
+		Player:  p,
+		Channel: e.Channel,
+		Args:    fields[1:],
+		Admin:   p.Verified && r.IsAdmin(p.GUID),
+	}
+	if cmd.Admin && !ctx.Admin {
+		glog.Warningf("ChatCmd: %v (%v) tried to use admin command %v", p.Name, p.GUID, cmd.Name)
+		r.Reply(p, "You are not allowed to use "+r.prefix+cmd.Name)
+		return
+	}
+	if !ctx.Admin && !r.cooldown(cmd, p) {
+		return
+	}
+	glog.V(1).Infof("ChatCmd: %v used %v %v", p.Name, cmd.Name, ctx.Args)
+	answer, err := cmd.Handler(ctx)
+	if err == ErrUsage {
+		answer = fmt.Sprintf("Usage: %s%s %s", r.prefix, cmd.Name, cmd.Usage)
+	} else if err != nil {
+		answer = err.Error()
+	}
+	if answer != "" {
+		r.Reply(p, answer)
+	}
+}
+
+//Reply sends a private message to the player
+func (r *Router) Reply(p players.Player, text string) {
+	r.client.RunCommand(fmt.Sprintf("say %d %s", p.Number, text), nil)
+}
+
+//cooldown returns whether the player may use the command again
+func (r *Router) cooldown(cmd Command, p players.Player) bool {
+	if cmd.Cooldown <= 0 {
+		return true
+	}
+	key := strings.ToLower(cmd.Name) + "|" + p.Name
+	r.cooldowns.Lock()
+	defer r.cooldowns.Unlock()
+	if last, ok := r.cooldowns.m[key]; ok && time.Since(last) < cmd.Cooldown {
+		return false
+	}
+	r.cooldowns.m[key] = time.Now()
+	return true
+}
+
+//FindPlayer resolves the player named at the start of args and returns the remaining args
+//Names may contain spaces, if no exact match is found a unique partial match is used
+func (r *Router) FindPlayer(args []string) (players.Player, []string, error) {
+	for i := len(args); i > 0; i-- {
+		if p, ok := r.roster.ByName(strings.Join(args[:i], " ")); ok {
+			return p, args[i:], nil
+		}
+	}
+	if len(args) == 0 {
+		return players.Player{}, nil, ErrUsage
+	}
+	var found []players.Player
+	for _, p := range r.roster.All() {
+		if strings.Contains(strings.ToLower(p.Name), strings.ToLower(args[0])) {
+			found = append(found, p)
+		}
+	}
+	if len(found) != 1 {
+		return players.Player{}, nil, ErrPlayerNotFound
+	}
+	return found[0], args[1:], nil
+}
+
+func (r *Router) help(ctx Context) (string, error) {
+	r.commands.RLock()
+	var names []string
+	for _, cmd := range r.commands.m {
+		if cmd.Admin && !ctx.Admin {
+			continue
+		}
+		names = append(names, r.prefix+cmd.Name)
+	}
+	r.commands.RUnlock()
+	sort.Strings(names)
+
+	if len(ctx.Args) > 0 {
+		r.commands.RLock()
+		cmd, ok := r.commands.m[strings.ToLower(strings.TrimPrefix(ctx.Args[0], r.prefix))]
+		r.commands.RUnlock()
+		if ok && (!cmd.Admin || ctx.Admin) {
+			return fmt.Sprintf("%s%s %s - %s", r.prefix, cmd.Name, cmd.Usage, cmd.Help), nil
+		}
+	}
+	return "Commands: " + strings.Join(names, " "), nil
+}
+
+func (s Static) command() Command {
+	response := s.Response
+	return Command{
+		Name:     s.Name,
+		Help:     s.Help,
+		Admin:    s.Admin,
+		Cooldown: time.Second * time.Duration(s.Cooldown),
+		Handler: func(ctx Context) (string, error) {
+			return message.Render(response, message.Vars{"name": ctx.Player.Name}), nil
+		},
+	}
+}
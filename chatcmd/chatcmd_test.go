@@ -0,0 +1,113 @@
+package chatcmd
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+func newRouter(t *testing.T) (*Router, *fakeClient) {
+	roster := players.NewRoster()
+	roster.Add(players.Player{Number: 1, Name: "Admin", GUID: "0123456789abcdef0123456789abcdef", Verified: true})
+	roster.Add(players.Player{Number: 2, Name: "Kevin Mc Name"})
+	roster.Add(players.Player{Number: 3, Name: "Steve"})
+	client := &fakeClient{}
+	r, err := New(Cfg{
+		Admins: []string{"0123456789ABCDEF0123456789ABCDEF"},
+		Commands: []Static{
+			{Name: "rules", Response: "Hi {name}, no teamkilling!", Cooldown: 60},
+		},
+	}, client, roster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, client
+}
+
+func Test_HandleChat(t *testing.T) {
+	var tests = []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"Steve", "!rules", []string{"say 3 Hi Steve, no teamkilling!"}},
+		{"Steve", "hello !rules", nil},
+		{"Steve", "!unknown", nil},
+		{"Steve", "!kick Admin", []string{"say 3 You are not allowed to use !kick"}},
+		{"Admin", "!kick kevin mc name teamkilling", []string{"kick 2 teamkilling", "say 1 Kicked Kevin Mc Name"}},
+		{"Admin", "!kick kev", []string{"kick 2 Kicked by Admin", "say 1 Kicked Kevin Mc Name"}},
+		{"Admin", "!ban Steve 60 cheating", []string{"ban 3 60 cheating", "say 1 Banned Steve for 60 minutes"}},
+		{"Admin", "!ban Steve", []string{"say 1 Usage: !ban <name> <minutes> [reason]"}},
+		{"Admin", "!kick nobody", []string{"say 1 Player not found"}},
+		{"Admin", "!addban 76561197960287930 0 cheating", []string{"addBan a357f31c8335a5263e0d816e64445b6a 0 cheating", "say 1 Banned a357f31c8335a5263e0d816e64445b6a for 0 minutes"}},
+		{"Admin", "!lock", []string{"#lock", "say 1 Server locked"}},
+		{"Admin", "!say restart soon", []string{"say -1 restart soon"}},
+		{"Steve", "!help", []string{"say 3 Commands: !help !rules"}},
+	}
+	for _, v := range tests {
+		r, client := newRouter(t)
+		r.HandleChat(events.Chat{Channel: "Global", Name: v.name, Text: v.text, Message: v.name + ": " + v.text})
+		if !reflect.DeepEqual(client.commands, v.expected) {
+			t.Errorf("%v: %q Expected: %v Got: %v", v.name, v.text, v.expected, client.commands)
+		}
+	}
+}
+
+func Test_Impersonation(t *testing.T) {
+	r, client := newRouter(t)
+	r.roster.Add(players.Player{Number: 4, Name: "Admin: !kick Steve"})
+	r.roster.Add(players.Player{Number: 5, Name: "Admin"})
+	r.HandleChat(events.Chat{Name: "Admin", Text: "!kick Steve: x", Message: "Admin: !kick Steve: x"})
+	r.HandleChat(events.Chat{Name: "Admin", Text: "!lock", Message: "Admin: !lock"})
+	if len(client.commands) != 0 {
+		t.Error("Expected ambiguous Senders to be refused Got:", client.commands)
+	}
+}
+
+func Test_UnverifiedAdmin(t *testing.T) {
+	r, client := newRouter(t)
+	//the GUID of an admin presented by another client, BattlEye did not verify it yet
+	r.roster.Add(players.Player{Number: 4, Name: "Spoofer", GUID: "0123456789abcdef0123456789abcdef"})
+	r.HandleChat(events.Chat{Name: "Spoofer", Text: "!kick Steve", Message: "Spoofer: !kick Steve"})
+	expected := []string{"say 4 You are not allowed to use !kick"}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_Cooldown(t *testing.T) {
+	r, client := newRouter(t)
+	r.HandleChat(events.Chat{Name: "Steve", Text: "!rules", Message: "Steve: !rules"})
+	r.HandleChat(events.Chat{Name: "Steve", Text: "!rules", Message: "Steve: !rules"})
+	if len(client.commands) != 1 {
+		t.Error("Expected cooldown to block second call Got:", client.commands)
+	}
+}
+
+func Test_Register(t *testing.T) {
+	r, client := newRouter(t)
+	err := r.Register(Command{Name: "ping", Handler: func(ctx Context) (string, error) {
+		return "pong", nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.HandleChat(events.Chat{Name: "Steve", Text: "!PING", Message: "Steve: !PING"})
+	if !reflect.DeepEqual(client.commands, []string{"say 3 pong"}) {
+		t.Error("Unexpected Commands:", client.commands)
+	}
+	if r.Register(Command{Name: "broken"}) == nil {
+		t.Error("Expected Error for Command without Handler")
+	}
+}
@@ -0,0 +1,233 @@
+package chatmod
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Penalty applied for an offence, Action is one of warn, kick or ban
+type Penalty struct {
+	Action  string
+	Minutes int
+	Message string
+}
+
+//Flood limits how many messages a player may send
+type Flood struct {
+	Messages int
+	Seconds  int
+	Repeats  int
+}
+
+//Cfg contains all data required by the Moderator
+type Cfg struct {
+	Words      []string
+	Patterns   []string
+	Flood      Flood
+	Penalties  []Penalty
+	ResetAfter time.Duration
+	Exempt     []string
+}
+
+//Config is the Interface providing Configs for the Moderator
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to punish players
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Moderator checks chat messages and escalates penalties for repeat offenders
+type Moderator struct {
+	client     Client
+	roster     *players.Roster
+	db         *playerdb.DB
+	words      message.Blocklist
+	patterns   []*regexp.Regexp
+	flood      Flood
+	penalties  []Penalty
+	resetAfter time.Duration
+	exempt     map[string]bool
+
+	history struct {
+		sync.Mutex
+		m map[string][]chatEntry
+	}
+}
+
+type chatEntry struct {
+	text string
+	time time.Time
+}
+
+//New creates a Moderator with given Config
+func New(c Config, client Client, roster *players.Roster, db *playerdb.DB) (*Moderator, error) {
+	cfg := c.GetConfig()
+	if len(cfg.Penalties) == 0 {
+		cfg.Penalties = []Penalty{{Action: "warn"}, {Action: "kick"}}
+	}
+	m := &Moderator{
+		client:     client,
+		roster:     roster,
+		db:         db,
+		flood:      cfg.Flood,
+		penalties:  cfg.Penalties,
+		resetAfter: cfg.ResetAfter,
+		exempt:     make(map[string]bool),
+	}
+	m.history.m = make(map[string][]chatEntry)
+	var words []string
+	for _, w := range cfg.Words {
+		words = append(words, message.Normalize(w)...)
+	}
+	m.words = message.NewBlocklist(words)
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat pattern %q: %v", p, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	for _, p := range m.penalties {
+		if p.Action != "warn" && p.Action != "kick" && p.Action != "ban" {
+			return nil, fmt.Errorf("unknown penalty action %q", p.Action)
+		}
+	}
+	for _, guid := range cfg.Exempt {
+		m.exempt[strings.ToLower(guid)] = true
+	}
+	return m, nil
+}
+
+//HandleChat checks a chat message and punishes the sender if required
+func (m *Moderator) HandleChat(e events.Chat) {
+	p, text, err := m.roster.Sender(e.Message)
+	if err != nil {
+		glog.V(2).Infof("ChatMod could not attribute %q: %v", e.Message, err)
+		return
+	}
+	if p.GUID != "" && m.exempt[p.GUID] {
+		return
+	}
+	reason := m.filter(text)
+	if reason == "" {
+		reason = m.flooding(p, text, time.Now())
+	}
+	if reason == "" {
+		return
+	}
+	m.punish(p, reason)
+}
+
+//filter returns the reason if the text contains blocked content
+func (m *Moderator) filter(text string) string {
+	for _, w := range message.Normalize(text) {
+		if m.words.Contains(w) {
+			return "Inappropriate Language"
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(text) {
+			return "Forbidden Content"
+		}
+	}
+	return ""
+}
+
+//flooding returns the reason if the player is spamming
+func (m *Moderator) flooding(p players.Player, text string, now time.Time) string {
+	if m.flood.Messages <= 0 && m.flood.Repeats <= 0 {
+		return ""
+	}
+	key := p.GUID
+	if key == "" {
+		key = p.Name
+	}
+	window := time.Second * time.Duration(m.flood.Seconds)
+	m.history.Lock()
+	defer m.history.Unlock()
+	var recent []chatEntry
+	for _, c := range m.history.m[key] {
+		if now.Sub(c.time) < window {
+			recent = append(recent, c)
+		}
+	}
+	recent = append(recent, chatEntry{text: text, time: now})
+	m.history.m[key] = recent
+
+	if m.flood.Messages > 0 && len(recent) >= m.flood.Messages {
+		m.history.m[key] = nil
+		return "Spam"
+	}
+	repeats := 0
+	for _, c := range recent {
+		if strings.EqualFold(c.text, text) {
+			repeats++
+		}
+	}
+	if m.flood.Repeats > 0 && repeats >= m.flood.Repeats {
+		m.history.m[key] = nil
+		return "Spam"
+	}
+	return ""
+}
+
+//punish the player with the penalty matching his offence count
+func (m *Moderator) punish(p players.Player, reason string) {
+	offences := 1
+	if p.GUID != "" && m.db != nil {
+		r, err := m.db.Update(p.GUID, func(r *playerdb.Record) {
+			if m.resetAfter > 0 && time.Since(r.LastOffence) > m.resetAfter {
+				r.Offences = 0
+			}
+			r.Offences++
+			r.LastOffence = time.Now()
+		})
+		if err != nil {
+			glog.Errorln("ChatMod could not store Offence:", err)
+		}
+		offences = r.Offences
+	}
+	step := offences
+	if step > len(m.penalties) {
+		step = len(m.penalties)
+	}
+	penalty := m.penalties[step-1]
+	text := penalty.Message
+	if text == "" {
+		text = reason
+	}
+	text = message.Render(text, message.Vars{
+		"name":     p.Name,
+		"reason":   reason,
+		"offences": offences,
+		"minutes":  penalty.Minutes,
+	})
+
+	glog.Infof("ChatMod: %v by %v (%v Offences), applying %v", reason, p.Name, offences, penalty.Action)
+	switch penalty.Action {
+	case "warn":
+		m.client.RunCommand(fmt.Sprintf("say %d %s", p.Number, text), nil)
+	case "kick":
+		m.client.RunCommand(fmt.Sprintf("kick %d %s", p.Number, text), nil)
+	case "ban":
+		m.client.RunCommand(fmt.Sprintf("ban %d %d %s", p.Number, penalty.Minutes, text), nil)
+	}
+}
@@ -0,0 +1,110 @@
+package chatmod
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+func Test_filter(t *testing.T) {
+	m, err := New(Cfg{
+		Words:    []string{"bad", "ass"},
+		Patterns: []string{`(?i)discord\.gg/\w+`},
+	}, &fakeClient{}, players.NewRoster(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		text     string
+		expected string
+	}{
+		{"you are b@@d", "Inappropriate Language"},
+		{"badminton anyone?", ""},
+		{"join discord.gg/abc", "Forbidden Content"},
+		{"hello", ""},
+		{"but I was there", ""},
+		{"go as fast as you can", ""},
+		{"pass the ammo, I was at the class", ""},
+		{"baaaad", "Inappropriate Language"},
+	}
+	for _, v := range tests {
+		if res := m.filter(v.text); res != v.expected {
+			t.Errorf("%q Expected: %q Got: %q", v.text, v.expected, res)
+		}
+	}
+}
+
+func Test_flooding(t *testing.T) {
+	m, err := New(Cfg{Flood: Flood{Messages: 3, Seconds: 10, Repeats: 2}}, &fakeClient{}, players.NewRoster(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := players.Player{Number: 1, Name: "Steve"}
+	now := time.Now()
+	if m.flooding(p, "a", now) != "" || m.flooding(p, "b", now) != "" {
+		t.Error("Expected no Spam")
+	}
+	if m.flooding(p, "c", now) != "Spam" {
+		t.Error("Expected Spam for too many Messages")
+	}
+	if m.flooding(p, "d", now.Add(time.Minute)) != "" {
+		t.Error("Expected no Spam after Window")
+	}
+	if m.flooding(p, "d", now.Add(time.Minute)) != "Spam" {
+		t.Error("Expected Spam for repeated Message")
+	}
+}
+
+func Test_Escalation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chatmod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := playerdb.Open(path.Join(dir, "players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roster := players.NewRoster()
+	roster.Add(players.Player{Number: 4, Name: "Steve", GUID: "0123456789abcdef0123456789abcdef"})
+	client := &fakeClient{}
+	m, err := New(Cfg{
+		Words: []string{"bad"},
+		Penalties: []Penalty{
+			{Action: "warn", Message: "{reason}, {name}!"},
+			{Action: "kick"},
+			{Action: "ban", Minutes: 60, Message: "Banned for {minutes} minutes"},
+		},
+	}, client, roster, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		m.HandleChat(events.Chat{Channel: "Global", Name: "Steve", Text: "bad", Message: "Steve: bad"})
+	}
+	expected := []string{
+		"say 4 Inappropriate Language, Steve!",
+		"kick 4 Inappropriate Language",
+		"ban 4 60 Banned for 60 minutes",
+		"ban 4 60 Banned for 60 minutes",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
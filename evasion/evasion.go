@@ -0,0 +1,211 @@
+package evasion
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/audit"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Cfg contains all data required by the Detector
+type Cfg struct {
+	Subnet  int
+	Kick    bool
+	Message string
+	Admins  []string
+	Refresh time.Duration
+}
+
+//Config is the Interface providing Configs for the Detector
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to read bans, alert admins and kick players
+type Client interface {
+	players.Querier
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Detector flags name changes and new GUIDs connecting from networks of banned players
+type Detector struct {
+	client  Client
+	roster  *players.Roster
+	db      *playerdb.DB
+	audit   *audit.Log
+	console io.Writer
+	mask    net.IPMask
+	kick    bool
+	message string
+	admins  map[string]bool
+	refresh time.Duration
+
+	banned struct {
+		sync.RWMutex
+		m map[string]bool
+	}
+}
+
+//New creates a Detector with given Config, alerts are written to console if not nil
+func New(c Config, client Client, roster *players.Roster, db *playerdb.DB, auditLog *audit.Log, console io.Writer) *Detector {
+	cfg := c.GetConfig()
+	if cfg.Subnet <= 0 || cfg.Subnet > 32 {
+		cfg.Subnet = 32
+	}
+	if cfg.Message == "" {
+		cfg.Message = "Suspected ban evasion, pending review by an admin"
+	}
+	if cfg.Refresh == 0 {
+		cfg.Refresh = time.Minute * 5
+	}
+	d := &Detector{
+		client:  client,
+		roster:  roster,
+		db:      db,
+		audit:   auditLog,
+		console: console,
+		mask:    net.CIDRMask(cfg.Subnet, 32),
+		kick:    cfg.Kick,
+		message: cfg.Message,
+		admins:  make(map[string]bool),
+		refresh: cfg.Refresh,
+	}
+	d.banned.m = make(map[string]bool)
+	for _, guid := range cfg.Admins {
+		d.admins[strings.ToLower(guid)] = true
+	}
+	return d
+}
+
+//Watch refreshes the list of banned GUIDs from the server
+func (d *Detector) Watch() {
+	for {
+		glog.V(10).Infoln("Looping in Evasion Watch")
+		res, err := d.client.Query("bans", players.QueryTimeout)
+		if err != nil {
+			glog.V(2).Infoln("Evasion could not retrieve Bans:", err)
+		} else {
+			d.SetBanned(ParseBans(res))
+		}
+		time.Sleep(d.refresh)
+	}
+}
+
+//SetBanned replaces the list of banned GUIDs
+func (d *Detector) SetBanned(guids []string) {
+	m := make(map[string]bool, len(guids))
+	for _, guid := range guids {
+		m[strings.ToLower(guid)] = true
+	}
+	d.banned.Lock()
+	d.banned.m = m
+	d.banned.Unlock()
+}
+
+//HandleGUIDVerified checks the player history of the verified GUID
+//The visit has to be recorded in the player database before
+func (d *Detector) HandleGUIDVerified(e events.GUIDVerified) {
+	r := d.db.Get(e.GUID)
+	p, _ := d.roster.ByNumber(e.Number)
+	if r.PreviousName != "" {
+		d.alert(fmt.Sprintf("%s was previously known as %s", e.Name, r.PreviousName), p, e.GUID, "namechange")
+	}
+	if r.Visits > 1 || p.IP == "" {
+		return
+	}
+	suspect, ok := d.evader(e.GUID, p.IP)
+	if !ok {
+		return
+	}
+	d.alert(fmt.Sprintf("%s (%s) connects from the network of banned player %s (%s)", e.Name, e.GUID, suspect.Name, suspect.GUID), p, e.GUID, "evasion")
+	if d.kick {
+		text := message.Render(d.message, message.Vars{"name": e.Name})
+		d.client.RunCommand(fmt.Sprintf("kick %d %s", e.Number, text), nil)
+		d.audit.Record(audit.Entry{
+			Source: "evasion",
+			Action: "kick",
+			Name:   e.Name,
+			GUID:   e.GUID,
+			IP:     p.IP,
+			Reason: "suspected evasion of " + suspect.GUID,
+		})
+	}
+}
+
+//evader returns the banned player whose network ip belongs to
+func (d *Detector) evader(guid, ip string) (playerdb.Record, bool) {
+	addr := net.ParseIP(ip).To4()
+	if addr == nil {
+		return playerdb.Record{}, false
+	}
+	network := addr.Mask(d.mask)
+	d.banned.RLock()
+	defer d.banned.RUnlock()
+	found := d.db.Find(func(r playerdb.Record) bool {
+		if r.GUID == guid || !d.banned.m[r.GUID] {
+			return false
+		}
+		for _, used := range r.IPs {
+			if u := net.ParseIP(used).To4(); u != nil && u.Mask(d.mask).Equal(network) {
+				return true
+			}
+		}
+		return false
+	})
+	if len(found) == 0 {
+		return playerdb.Record{}, false
+	}
+	return found[0], true
+}
+
+//alert the online admins, the event stream and the audit trail
+func (d *Detector) alert(text string, p players.Player, guid, reason string) {
+	glog.Warningln("Alert:", text)
+	if d.console != nil {
+		fmt.Fprintln(d.console, "Alert:", text)
+	}
+	for _, admin := range d.roster.All() {
+		if admin.Verified && d.admins[admin.GUID] {
+			d.client.RunCommand(fmt.Sprintf("say %d [Alert] %s", admin.Number, text), nil)
+		}
+	}
+	d.audit.Record(audit.Entry{
+		Source: "evasion",
+		Action: "alert",
+		Name:   p.Name,
+		GUID:   guid,
+		IP:     p.IP,
+		Reason: reason,
+	})
+}
+
+// 0  0123456789abcdef0123456789abcdef perm Cheating
+var banLine = regexp.MustCompile(`^\d+\s+([0-9a-fA-F]{32})\s`)
+
+//ParseBans returns the GUIDs listed in the output of the bans command
+func ParseBans(list string) []string {
+	var guids []string
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		if m := banLine.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+			guids = append(guids, strings.ToLower(m[1]))
+		}
+	}
+	return guids
+}
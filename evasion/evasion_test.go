@@ -0,0 +1,118 @@
+package evasion
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+const bansOutput = `GUID Bans:
+[#] [GUID] [Minutes left] [Reason]
+----------------------------------------
+0  BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB perm Cheating
+1  cccccccccccccccccccccccccccccccc 120 Teamkilling
+
+IP Bans:
+[#] [IP Address] [Minutes left] [Reason]
+----------------------------------------------
+0  10.9.9.9        perm Cheating
+`
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) Query(cmd string, timeout time.Duration) (string, error) {
+	return bansOutput, nil
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+const (
+	adminGUID  = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	bannedGUID = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	newGUID    = "dddddddddddddddddddddddddddddddd"
+)
+
+func Test_ParseBans(t *testing.T) {
+	expected := []string{bannedGUID, "cccccccccccccccccccccccccccccccc"}
+	if res := ParseBans(bansOutput); !reflect.DeepEqual(res, expected) {
+		t.Error("Expected:", expected, "Got:", res)
+	}
+}
+
+func newDetector(t *testing.T, dir string, kick bool) (*Detector, *fakeClient, *playerdb.DB, *players.Roster, *bytes.Buffer) {
+	db, err := playerdb.Open(path.Join(dir, "players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Visit(bannedGUID, "Cheater", "10.1.1.20")
+	roster := players.NewRoster()
+	roster.Add(players.Player{Number: 0, Name: "Admin", GUID: adminGUID, Verified: true})
+	client := &fakeClient{}
+	console := &bytes.Buffer{}
+	d := New(Cfg{Subnet: 24, Kick: kick, Admins: []string{adminGUID}}, client, roster, db, nil, console)
+	d.SetBanned(ParseBans(bansOutput))
+	return d, client, db, roster, console
+}
+
+func Test_Evasion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evasion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	d, client, db, roster, console := newDetector(t, dir, true)
+
+	roster.Add(players.Player{Number: 4, Name: "Innocent", IP: "10.1.2.20"})
+	db.Visit(newGUID, "Innocent", "10.1.2.20")
+	d.HandleGUIDVerified(events.GUIDVerified{Number: 4, Name: "Innocent", GUID: newGUID})
+	if len(client.commands) != 0 {
+		t.Fatal("Expected no Alert for other Subnet Got:", client.commands)
+	}
+
+	guid := "eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	roster.Add(players.Player{Number: 5, Name: "NewGuy", IP: "10.1.1.99"})
+	db.Visit(guid, "NewGuy", "10.1.1.99")
+	d.HandleGUIDVerified(events.GUIDVerified{Number: 5, Name: "NewGuy", GUID: guid})
+	text := "NewGuy (" + guid + ") connects from the network of banned player Cheater (" + bannedGUID + ")"
+	expected := []string{
+		"say 0 [Alert] " + text,
+		"kick 5 Suspected ban evasion, pending review by an admin",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+	if console.String() != "Alert: "+text+"\n" {
+		t.Error("Unexpected Console Output:", console.String())
+	}
+}
+
+func Test_NameChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evasion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	d, client, db, roster, _ := newDetector(t, dir, false)
+
+	db.Visit(newGUID, "Steve", "10.5.5.5")
+	roster.Add(players.Player{Number: 3, Name: "Kevin", IP: "10.5.5.5"})
+	db.Visit(newGUID, "Kevin", "10.5.5.5")
+	d.HandleGUIDVerified(events.GUIDVerified{Number: 3, Name: "Kevin", GUID: newGUID})
+	expected := []string{"say 0 [Alert] Kevin was previously known as Steve"}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+//Dispatcher parses server messages written to it and passes them on to the registered handlers
+//Handlers are called sequentially from a separate goroutine so they may safely query the server
+type Dispatcher struct {
+	queue chan interface{}
+
+	handlers struct {
+		sync.RWMutex
+		chat         []func(Chat)
+		connect      []func(Connect)
+		guidVerified []func(GUIDVerified)
+		disconnect   []func(Disconnect)
+		adminLogin   []func(AdminLogin)
+	}
+}
+
+//NewDispatcher creates and starts a Dispatcher
+func NewDispatcher() *Dispatcher {
+	d := &Dispatcher{
+		queue: make(chan interface{}, 256),
+	}
+	go d.loop()
+	return d
+}
+
+//OnChat registers a handler for chat messages
+func (d *Dispatcher) OnChat(h func(Chat)) {
+	d.handlers.Lock()
+	d.handlers.chat = append(d.handlers.chat, h)
+	d.handlers.Unlock()
+}
+
+//OnConnect registers a handler for player connects
+func (d *Dispatcher) OnConnect(h func(Connect)) {
+	d.handlers.Lock()
+	d.handlers.connect = append(d.handlers.connect, h)
+	d.handlers.Unlock()
+}
+
+//OnGUIDVerified registers a handler for verified player GUIDs
+func (d *Dispatcher) OnGUIDVerified(h func(GUIDVerified)) {
+	d.handlers.Lock()
+	d.handlers.guidVerified = append(d.handlers.guidVerified, h)
+	d.handlers.Unlock()
+}
+
+//OnDisconnect registers a handler for player disconnects
+func (d *Dispatcher) OnDisconnect(h func(Disconnect)) {
+	d.handlers.Lock()
+	d.handlers.disconnect = append(d.handlers.disconnect, h)
+	d.handlers.Unlock()
+}
+
+//OnAdminLogin registers a handler for RCon admin logins
+func (d *Dispatcher) OnAdminLogin(h func(AdminLogin)) {
+	d.handlers.Lock()
+	d.handlers.adminLogin = append(d.handlers.adminLogin, h)
+	d.handlers.Unlock()
+}
+
+//Write parses each line in p and queues the resulting events
+func (d *Dispatcher) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		e := Parse(string(line))
+		if e == nil {
+			continue
+		}
+		select {
+		case d.queue <- e:
+		default:
+			glog.Warningln("Event Queue is full, dropping Event:", e)
+		}
+	}
+	return len(p), nil
+}
+
+func (d *Dispatcher) loop() {
+	for e := range d.queue {
+		glog.V(10).Infoln("Looping in Dispatcher")
+		d.dispatch(e)
+	}
+}
+
+func (d *Dispatcher) dispatch(e interface{}) {
+	d.handlers.RLock()
+	defer d.handlers.RUnlock()
+	switch e := e.(type) {
+	case Chat:
+		for _, h := range d.handlers.chat {
+			h(e)
+		}
+	case Connect:
+		for _, h := range d.handlers.connect {
+			h(e)
+		}
+	case GUIDVerified:
+		for _, h := range d.handlers.guidVerified {
+			h(e)
+		}
+	case Disconnect:
+		for _, h := range d.handlers.disconnect {
+			h(e)
+		}
+	case AdminLogin:
+		for _, h := range d.handlers.adminLogin {
+			h(e)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//Chat message sent by a player
+//Name and Text are split at the first ": " and may be wrong for names containing it,
+//use Message with players.Roster.Sender to attribute the message to a player
+type Chat struct {
+	Channel string
+	Name    string
+	Text    string
+	//Message is the name of the sender followed by ": " and the text
+	Message string
+}
+
+//Connect of a player to the server
+type Connect struct {
+	Number int
+	Name   string
+	IP     string
+	Port   string
+}
+
+//GUIDVerified after BattlEye checked the players GUID
+type GUIDVerified struct {
+	Number int
+	Name   string
+	GUID   string
+}
+
+//Disconnect of a player from the server
+type Disconnect struct {
+	Number int
+	Name   string
+}
+
+//AdminLogin of an RCon client
+type AdminLogin struct {
+	Number int
+	IP     string
+	Port   string
+}
+
+var (
+	// (Global) Steve: hello
+	chatPattern = regexp.MustCompile(`^\((Global|Side|Command|Group|Vehicle|Direct|Unknown)\) ((.+?): (.*))$`)
+	// Player #0 Steve (127.0.0.1:2304) connected
+	connectPattern = regexp.MustCompile(`^Player #(\d+) (.+) \(([0-9.]+):(\d+)\) connected$`)
+	// Verified GUID (0123456789abcdef0123456789abcdef) of player #0 Steve
+	guidPattern = regexp.MustCompile(`^Verified GUID \(([0-9a-fA-F]{32})\) of player #(\d+) (.+)$`)
+	// Player #0 Steve disconnected
+	disconnectPattern = regexp.MustCompile(`^Player #(\d+) (.+) disconnected$`)
+	// RCon admin #0 (127.0.0.1:50000) logged in
+	adminLoginPattern = regexp.MustCompile(`^RCon admin #(\d+) \(([0-9.]+):(\d+)\) logged in$`)
+)
+
+//Parse a server message into one of the event types or nil if unknown
+func Parse(line string) interface{} {
+	line = strings.TrimRight(line, "\r\n")
+	if m := chatPattern.FindStringSubmatch(line); m != nil {
+		return Chat{Channel: m[1], Name: m[3], Text: m[4], Message: m[2]}
+	}
+	if m := connectPattern.FindStringSubmatch(line); m != nil {
+		return Connect{Number: atoi(m[1]), Name: m[2], IP: m[3], Port: m[4]}
+	}
+	if m := guidPattern.FindStringSubmatch(line); m != nil {
+		return GUIDVerified{Number: atoi(m[2]), Name: m[3], GUID: strings.ToLower(m[1])}
+	}
+	if m := disconnectPattern.FindStringSubmatch(line); m != nil {
+		return Disconnect{Number: atoi(m[1]), Name: m[2]}
+	}
+	if m := adminLoginPattern.FindStringSubmatch(line); m != nil {
+		return AdminLogin{Number: atoi(m[1]), IP: m[2], Port: m[3]}
+	}
+	return nil
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
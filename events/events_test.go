@@ -0,0 +1,44 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Parse(t *testing.T) {
+	var tests = []struct {
+		line     string
+		expected interface{}
+	}{
+		{"(Global) Steve: hello there\n", Chat{Channel: "Global", Name: "Steve", Text: "hello there", Message: "Steve: hello there"}},
+		{"(Side) Kevin: a: b", Chat{Channel: "Side", Name: "Kevin", Text: "a: b", Message: "Kevin: a: b"}},
+		{"Player #3 Steve (127.0.0.1:2304) connected", Connect{Number: 3, Name: "Steve", IP: "127.0.0.1", Port: "2304"}},
+		{"Player #12 Name (With) Braces (10.0.0.1:2316) connected", Connect{Number: 12, Name: "Name (With) Braces", IP: "10.0.0.1", Port: "2316"}},
+		{"Verified GUID (0123456789ABCDEF0123456789abcdef) of player #3 Steve", GUIDVerified{Number: 3, Name: "Steve", GUID: "0123456789abcdef0123456789abcdef"}},
+		{"Player #3 Steve disconnected", Disconnect{Number: 3, Name: "Steve"}},
+		{"RCon admin #0 (127.0.0.1:50000) logged in", AdminLogin{Number: 0, IP: "127.0.0.1", Port: "50000"}},
+		{"Unknown message", nil},
+	}
+	for _, v := range tests {
+		if res := Parse(v.line); res != v.expected {
+			t.Errorf("%q Expected: %v Got: %v", v.line, v.expected, res)
+		}
+	}
+}
+
+func Test_Dispatcher(t *testing.T) {
+	d := NewDispatcher()
+	received := make(chan Chat, 1)
+	d.OnChat(func(c Chat) {
+		received <- c
+	})
+	d.Write([]byte("(Global) Steve: hello\n"))
+	select {
+	case c := <-received:
+		if c.Text != "hello" {
+			t.Error("Expected: hello Got:", c.Text)
+		}
+	case <-time.After(time.Second):
+		t.Error("Chat Event not dispatched")
+	}
+}
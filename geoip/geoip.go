@@ -0,0 +1,72 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+)
+
+//ErrInvalidIP is returned for addresses which can not be parsed
+var ErrInvalidIP = errors.New("Invalid IP Address")
+
+//Country as stored in the database
+type Country struct {
+	Code string
+	Name string
+}
+
+//Locator resolves IP addresses to countries
+type Locator interface {
+	Country(ip string) (Country, error)
+}
+
+//DB looks up countries in a local MaxMind format (.mmdb) database
+type DB struct {
+	reader *maxminddb.Reader
+}
+
+type record struct {
+	Country struct {
+		IsoCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+}
+
+//Open the database file at path
+func Open(path string) (*DB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{reader: reader}, nil
+}
+
+//Country returns the country of ip, unknown addresses return an empty Country
+func (db *DB) Country(ip string) (Country, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return Country{}, ErrInvalidIP
+	}
+	var r record
+	if err := db.reader.Lookup(addr, &r); err != nil {
+		return Country{}, err
+	}
+	return Country{Code: r.Country.IsoCode, Name: r.Country.Names["en"]}, nil
+}
+
+//Close the database
+func (db *DB) Close() error {
+	return db.reader.Close()
+}
+
+//String returns the country as "Name (CODE)" or "Unknown"
+func (c Country) String() string {
+	if c.Code == "" {
+		return "Unknown"
+	}
+	if c.Name == "" {
+		return c.Code
+	}
+	return c.Name + " (" + c.Code + ")"
+}
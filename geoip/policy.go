@@ -0,0 +1,137 @@
+package geoip
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/audit"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Actions of the Policy
+const (
+	Allow = "allow"
+	Warn  = "warn"
+	Kick  = "kick"
+)
+
+//Cfg contains all data required by the Policy
+//Listed applies to players from Countries, Unlisted to all others and Unknown if the country could not be resolved
+type Cfg struct {
+	Countries []string
+	Listed    string
+	Unlisted  string
+	Unknown   string
+	Message   string
+}
+
+//Config is the Interface providing Configs for the Policy
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to warn and kick players
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Policy resolves the country of connecting players and applies the configured action
+type Policy struct {
+	client    Client
+	locator   Locator
+	roster    *players.Roster
+	audit     *audit.Log
+	console   io.Writer
+	countries map[string]bool
+	listed    string
+	unlisted  string
+	unknown   string
+	message   string
+}
+
+//NewPolicy creates a Policy with given Config, join events with the country are written to console if not nil
+func NewPolicy(c Config, client Client, locator Locator, roster *players.Roster, auditLog *audit.Log, console io.Writer) (*Policy, error) {
+	cfg := c.GetConfig()
+	p := &Policy{
+		client:    client,
+		locator:   locator,
+		roster:    roster,
+		audit:     auditLog,
+		console:   console,
+		countries: make(map[string]bool),
+		listed:    defaultAction(cfg.Listed),
+		unlisted:  defaultAction(cfg.Unlisted),
+		unknown:   defaultAction(cfg.Unknown),
+		message:   cfg.Message,
+	}
+	if p.message == "" {
+		p.message = "Players from {country} are not allowed on this server"
+	}
+	for _, a := range []string{p.listed, p.unlisted, p.unknown} {
+		if a != Allow && a != Warn && a != Kick {
+			return nil, fmt.Errorf("unknown geoip action %q", a)
+		}
+	}
+	for _, code := range cfg.Countries {
+		p.countries[strings.ToUpper(code)] = true
+	}
+	return p, nil
+}
+
+//HandleConnect resolves the country of the connecting player and applies the policy
+func (p *Policy) HandleConnect(e events.Connect) {
+	country, err := p.locator.Country(e.IP)
+	if err != nil {
+		glog.V(2).Infof("GeoIP could not resolve %v: %v", e.IP, err)
+	}
+	p.roster.SetCountry(e.Number, country.Code)
+	if p.console != nil {
+		fmt.Fprintf(p.console, "Player #%d %s connected from %s\n", e.Number, e.Name, country)
+	}
+
+	action := p.action(country.Code)
+	if action == Allow {
+		return
+	}
+	text := message.Render(p.message, message.Vars{"name": e.Name, "country": country, "code": country.Code})
+	glog.Infof("GeoIP: %v %v from %v", action, e.Name, country)
+	if action == Warn {
+		p.client.RunCommand(fmt.Sprintf("say %d %s", e.Number, text), nil)
+		return
+	}
+	p.client.RunCommand(fmt.Sprintf("kick %d %s", e.Number, text), nil)
+	p.audit.Record(audit.Entry{
+		Source: "geoip",
+		Action: "kick",
+		Name:   e.Name,
+		IP:     e.IP,
+		Reason: "country " + country.String(),
+	})
+}
+
+//action returns the action for players from the country with the given code
+func (p *Policy) action(code string) string {
+	if code == "" {
+		return p.unknown
+	}
+	if p.countries[code] {
+		return p.listed
+	}
+	return p.unlisted
+}
+
+func defaultAction(a string) string {
+	if a == "" {
+		return Allow
+	}
+	return strings.ToLower(a)
+}
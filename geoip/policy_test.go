@@ -0,0 +1,69 @@
+package geoip
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+type fakeLocator map[string]Country
+
+func (f fakeLocator) Country(ip string) (Country, error) {
+	return f[ip], nil
+}
+
+func Test_HandleConnect(t *testing.T) {
+	locator := fakeLocator{
+		"1.1.1.1": {Code: "DE", Name: "Germany"},
+		"2.2.2.2": {Code: "US", Name: "United States"},
+		"3.3.3.3": {Code: "RU", Name: "Russia"},
+	}
+	client := &fakeClient{}
+	roster := players.NewRoster()
+	console := &bytes.Buffer{}
+	p, err := NewPolicy(Cfg{
+		Countries: []string{"de", "at"},
+		Listed:    "allow",
+		Unlisted:  "kick",
+		Unknown:   "warn",
+		Message:   "{country} not allowed",
+	}, client, locator, roster, nil, console)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ip := range []string{"1.1.1.1", "2.2.2.2", "4.4.4.4"} {
+		roster.Add(players.Player{Number: i, Name: "P", IP: ip})
+		p.HandleConnect(events.Connect{Number: i, Name: "P", IP: ip})
+	}
+	expected := []string{
+		"kick 1 United States (US) not allowed",
+		"say 2 Unknown not allowed",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+	if pl, _ := roster.ByNumber(0); pl.Country != "DE" {
+		t.Error("Expected roster country DE Got:", pl.Country)
+	}
+	if console.String() != "Player #0 P connected from Germany (DE)\nPlayer #1 P connected from United States (US)\nPlayer #2 P connected from Unknown\n" {
+		t.Error("Unexpected Console Output:", console.String())
+	}
+}
+
+func Test_NewPolicyInvalidAction(t *testing.T) {
+	if _, err := NewPolicy(Cfg{Listed: "ban"}, &fakeClient{}, fakeLocator{}, players.NewRoster(), nil, nil); err == nil {
+		t.Error("Expected Error for unknown action")
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/adminguard"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/webhook"
+)
+
+var webhooks *webhook.Sender
+
+func runAdminGuard(dispatcher *events.Dispatcher, console io.Writer) error {
+	agcfg := adminguard.Cfg{
+		Trusted:   cfg.GetStringSlice("adminguard.trusted"),
+		MaxLogins: cfg.GetInt("adminguard.maxLogins"),
+		Window:    time.Second * time.Duration(cfg.GetInt("adminguard.window")),
+	}
+	fmt.Printf("\nAdminGuard Config: \n"+
+		"Trusted: %v \n"+
+		"Max successful Logins: %v per %v \n\n",
+		agcfg.Trusted, agcfg.MaxLogins, agcfg.Window)
+	guard, err := adminguard.New(agcfg, getAuditLog(), getWebhooks(), console)
+	if err != nil {
+		return err
+	}
+	dispatcher.OnAdminLogin(guard.HandleAdminLogin)
+	return nil
+}
+
+//getWebhooks returns the Sender for all configured webhooks or nil if there are none
+func getWebhooks() *webhook.Sender {
+	if urls := cfg.GetStringSlice("webhooks"); webhooks == nil && len(urls) > 0 {
+		webhooks = webhook.New(urls)
+	}
+	return webhooks
+}
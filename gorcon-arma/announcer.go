@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/announcer"
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/procwatch"
+)
+
+func runAnnouncer(client *rcon.Client, roster *players.Roster, watcher *procwatch.Watcher) error {
+	acfg := announcer.Cfg{
+		Messages:   cfg.GetStringSlice("announcer.messages"),
+		File:       cfg.GetString("announcer.file"),
+		Interval:   time.Second * time.Duration(cfg.GetInt("announcer.interval")),
+		Random:     cfg.GetBool("announcer.random"),
+		MinPlayers: cfg.GetInt("announcer.minPlayers"),
+	}
+	fmt.Printf("\nAnnouncer Config: \n"+
+		"Messages: %v \n"+
+		"File: %v \n"+
+		"Interval: %v \n"+
+		"Random: %v \n"+
+		"Min Players: %v \n\n",
+		len(acfg.Messages), acfg.File, acfg.Interval, acfg.Random, acfg.MinPlayers)
+	var uptime announcer.Uptime
+	if watcher != nil && cfg.GetBool("watcher.enabled") {
+		uptime = watcher
+	}
+	a := announcer.New(acfg, client, roster, restartsOf(watcher), uptime)
+	go a.Run()
+	return nil
+}
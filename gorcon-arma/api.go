@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/api"
+)
+
+var apiServer *api.Server
+
+//getAPI returns the API server or nil if it is disabled
+func getAPI() *api.Server {
+	if apiServer == nil && cfg.GetBool("api.enabled") {
+		apiServer = api.New(api.Cfg{
+			Listen: cfg.GetString("api.listen"),
+			Token:  cfg.GetString("api.token"),
+		})
+	}
+	return apiServer
+}
+
+//runAPI serves the endpoints registered by the enabled features
+func runAPI() {
+	fmt.Printf("\nAPI Config: \n"+
+		"Listen: %v \n"+
+		"Token: %v \n\n",
+		cfg.GetString("api.listen"), cfg.GetString("api.token") != "")
+	server := getAPI()
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			glog.Errorln("API stopped:", err)
+		}
+	}()
+}
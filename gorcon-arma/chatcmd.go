@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/chatcmd"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/welcome"
+)
+
+func runChatCmd(client *rcon.Client, dispatcher *events.Dispatcher, roster *players.Roster, restarts welcome.Restarts) (*chatcmd.Router, error) {
+	var commands []chatcmd.Static
+	if err := cfg.UnmarshalKey("chatcmd.commands", &commands); err != nil {
+		return nil, err
+	}
+	cccfg := chatcmd.Cfg{
+		Prefix:   cfg.GetString("chatcmd.prefix"),
+		Admins:   getGUIDs("admins"),
+		Commands: commands,
+	}
+	fmt.Printf("\nChatCmd Config: \n"+
+		"Prefix: %v \n"+
+		"Admins: %v \n"+
+		"Custom Commands: %v \n\n",
+		cccfg.Prefix, len(cccfg.Admins), len(cccfg.Commands))
+	router, err := chatcmd.New(cccfg, client, roster)
+	if err != nil {
+		return nil, err
+	}
+	if restarts != nil {
+		router.Register(chatcmd.Command{
+			Name:     "restart",
+			Help:     "Shows the time until the next restart",
+			Cooldown: time.Second * 30,
+			Handler: func(ctx chatcmd.Context) (string, error) {
+				return "Next restart in " + welcome.NextRestart(restarts), nil
+			},
+		})
+	}
+	dispatcher.OnChat(router.HandleChat)
+	return router, nil
+}
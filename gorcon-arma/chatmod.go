@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/chatmod"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+func runChatMod(client *rcon.Client, dispatcher *events.Dispatcher, roster *players.Roster) error {
+	db, err := getPlayerDB()
+	if err != nil {
+		return err
+	}
+	var penalties []chatmod.Penalty
+	if err := cfg.UnmarshalKey("chatmod.penalties", &penalties); err != nil {
+		return err
+	}
+	cmcfg := chatmod.Cfg{
+		Words:    cfg.GetStringSlice("chatmod.words"),
+		Patterns: cfg.GetStringSlice("chatmod.patterns"),
+		Flood: chatmod.Flood{
+			Messages: cfg.GetInt("chatmod.flood.messages"),
+			Seconds:  cfg.GetInt("chatmod.flood.seconds"),
+			Repeats:  cfg.GetInt("chatmod.flood.repeats"),
+		},
+		Penalties:  penalties,
+		ResetAfter: time.Hour * time.Duration(cfg.GetInt("chatmod.resetAfter")),
+		Exempt:     getGUIDs("chatmod.exempt"),
+	}
+	fmt.Printf("\nChatMod Config: \n"+
+		"Blocked Words: %v \n"+
+		"Blocked Patterns: %v \n"+
+		"Penalties: %v \n\n",
+		len(cmcfg.Words), len(cmcfg.Patterns), cmcfg.Penalties)
+	moderator, err := chatmod.New(cmcfg, client, roster, db)
+	if err != nil {
+		return err
+	}
+	dispatcher.OnChat(moderator.HandleChat)
+	return nil
+}
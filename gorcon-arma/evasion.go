@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/evasion"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+func runEvasion(client *rcon.Client, dispatcher *events.Dispatcher, roster *players.Roster, console io.Writer) error {
+	db, err := getPlayerDB()
+	if err != nil {
+		return err
+	}
+	ecfg := evasion.Cfg{
+		Subnet:  cfg.GetInt("evasion.subnet"),
+		Kick:    cfg.GetBool("evasion.kick"),
+		Message: cfg.GetString("evasion.message"),
+		Admins:  getGUIDs("admins"),
+		Refresh: time.Second * time.Duration(cfg.GetInt("evasion.refresh")),
+	}
+	fmt.Printf("\nEvasion Config: \n"+
+		"Subnet: /%v \n"+
+		"Kick: %v \n"+
+		"Ban Refresh: %v \n\n",
+		ecfg.Subnet, ecfg.Kick, ecfg.Refresh)
+	if !cfg.GetBool("arma.showEvents") {
+		console = nil
+	}
+	detector := evasion.New(ecfg, client, roster, db, getAuditLog(), console)
+	dispatcher.OnGUIDVerified(detector.HandleGUIDVerified)
+	go detector.Watch()
+	return nil
+}
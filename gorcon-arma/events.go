@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/playnet-public/gorcon-arma/audit"
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+var playerDB *playerdb.DB
+var auditLog *audit.Log
+
+//runEvents connects the server messages to the event dispatcher, the player roster and the player database
+func runEvents(client *rcon.Client, console io.Writer, showChat, showEvents bool) (*events.Dispatcher, *players.Roster, error) {
+	db, err := getPlayerDB()
+	if err != nil {
+		return nil, nil, err
+	}
+	dispatcher := events.NewDispatcher()
+	roster := trackRoster(client, dispatcher)
+	dispatcher.OnGUIDVerified(func(e events.GUIDVerified) {
+		p, _ := roster.ByNumber(e.Number)
+		if _, err := db.Visit(e.GUID, e.Name, p.IP); err != nil {
+			glog.Errorln("Could not record Visit:", err)
+		}
+	})
+
+	var chatWriter, eventWriter io.Writer = dispatcher, dispatcher
+	if showChat {
+		chatWriter = io.MultiWriter(console, dispatcher)
+	}
+	if showEvents {
+		eventWriter = io.MultiWriter(console, dispatcher)
+	}
+	client.SetChatWriter(chatWriter)
+	client.SetEventWriter(eventWriter)
+	return dispatcher, roster, nil
+}
+
+//trackRoster keeps a roster of the players on the server of client
+//Its handlers are registered first so later handlers find the player in the roster
+func trackRoster(client *rcon.Client, dispatcher *events.Dispatcher) *players.Roster {
+	roster := players.NewRoster()
+	dispatcher.OnConnect(func(e events.Connect) {
+		roster.Add(players.Player{Number: e.Number, Name: e.Name, IP: e.IP, Port: e.Port})
+	})
+	dispatcher.OnGUIDVerified(func(e events.GUIDVerified) {
+		roster.SetGUID(e.Number, e.GUID)
+	})
+	dispatcher.OnDisconnect(func(e events.Disconnect) {
+		roster.Remove(e.Number)
+	})
+	go roster.Poll(client, time.Minute)
+	return roster
+}
+
+//getPlayerDB opens the player database on first use
+func getPlayerDB() (*playerdb.DB, error) {
+	if playerDB != nil {
+		return playerDB, nil
+	}
+	path := cfg.GetString("playerdb.path")
+	if path == "" {
+		path = "players.json"
+	}
+	db, err := playerdb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	playerDB = db
+	return db, nil
+}
+
+//getAuditLog returns the audit trail or nil if it is disabled
+func getAuditLog() *audit.Log {
+	if auditLog == nil && cfg.GetBool("audit.enabled") {
+		auditLog = audit.New(cfg.GetString("audit.path"))
+	}
+	return auditLog
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/chatcmd"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/geoip"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+func runGeoIP(client *rcon.Client, dispatcher *events.Dispatcher, roster *players.Roster, router *chatcmd.Router, console io.Writer) error {
+	database := cfg.GetString("geoip.database")
+	db, err := geoip.Open(database)
+	if err != nil {
+		return err
+	}
+	gcfg := geoip.Cfg{
+		Countries: cfg.GetStringSlice("geoip.policy.countries"),
+		Listed:    cfg.GetString("geoip.policy.listed"),
+		Unlisted:  cfg.GetString("geoip.policy.unlisted"),
+		Unknown:   cfg.GetString("geoip.policy.unknown"),
+		Message:   cfg.GetString("geoip.policy.message"),
+	}
+	fmt.Printf("\nGeoIP Config: \n"+
+		"Database: %v \n"+
+		"Countries: %v \n"+
+		"Listed/Unlisted/Unknown: %v/%v/%v \n\n",
+		database, gcfg.Countries, gcfg.Listed, gcfg.Unlisted, gcfg.Unknown)
+	if !cfg.GetBool("arma.showEvents") {
+		console = nil
+	}
+	policy, err := geoip.NewPolicy(gcfg, client, db, roster, getAuditLog(), console)
+	if err != nil {
+		return err
+	}
+	dispatcher.OnConnect(policy.HandleConnect)
+	if router != nil {
+		router.Register(chatcmd.Command{
+			Name:  "players",
+			Help:  "Lists all players with their country",
+			Admin: true,
+			Handler: func(ctx chatcmd.Context) (string, error) {
+				return listCountries(roster, db), nil
+			},
+		})
+	}
+	return nil
+}
+
+//listCountries returns all players with their country, resolving players joined before gorcon-arma started
+func listCountries(roster *players.Roster, db geoip.Locator) string {
+	list := roster.All()
+	sort.Slice(list, func(i, j int) bool { return list[i].Number < list[j].Number })
+	entries := make([]string, 0, len(list))
+	for _, p := range list {
+		if p.Country == "" {
+			if c, err := db.Country(p.IP); err == nil {
+				p.Country = c.Code
+				roster.SetCountry(p.Number, c.Code)
+			}
+		}
+		if p.Country == "" {
+			p.Country = "??"
+		}
+		entries = append(entries, fmt.Sprintf("#%d %s (%s)", p.Number, p.Name, p.Country))
+	}
+	return fmt.Sprintf("%d Players: %s", len(list), strings.Join(entries, ", "))
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/chatcmd"
 	"github.com/playnet-public/gorcon-arma/procwatch"
 
 	"github.com/golang/glog"
@@ -32,6 +33,30 @@ func main() {
 	defer glog.Flush()
 	glog.CopyStandardLogTo("info")
 	flag.Parse()
+	switch flag.Arg(0) {
+	case "guid":
+		if err := runGUID(flag.Args()[1:]); err != nil {
+			glog.Fatal(err)
+		}
+		return
+	case "schedule":
+		if err := runSchedule(flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	case "jobs":
+		if err := runJobs(flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	case "warn", "note", "notes":
+		if err := runRemarks(flag.Args()); err != nil {
+			glog.Fatal(err)
+		}
+		return
+	}
 	fmt.Println("-- PlayNet GoRcon-ArmA - OpenSource Server Manager --")
 	fmt.Println("Version:", version)
 	fmt.Println("SourceCode: http://bit.ly/gorcon-code")
@@ -58,12 +83,29 @@ func do() error {
 	logToConsole := cfg.GetBool("watcher.logToConsole")
 	showChat := cfg.GetBool("arma.showChat")
 	showEvents := cfg.GetBool("arma.showEvents")
+	usePingKick := cfg.GetBool("pingkick.enabled")
+	useChatMod := cfg.GetBool("chatmod.enabled")
+	useChatCmd := cfg.GetBool("chatcmd.enabled")
+	useWelcome := cfg.GetBool("welcome.enabled")
+	useSlots := cfg.GetBool("slots.enabled")
+	useVote := cfg.GetBool("vote.enabled")
+	useNamePolicy := cfg.GetBool("namepolicy.enabled")
+	useIPFilter := cfg.GetBool("ipfilter.enabled")
+	useGeoIP := cfg.GetBool("geoip.enabled")
+	useEvasion := cfg.GetBool("evasion.enabled")
+	useWarnings := cfg.GetBool("warnings.enabled")
+	useAdminGuard := cfg.GetBool("adminguard.enabled")
+	useAnnouncer := cfg.GetBool("announcer.enabled")
+	useRelay := cfg.GetBool("relay.enabled")
+	useRotation := cfg.GetBool("rotation.enabled")
+	useAPI := cfg.GetBool("api.enabled")
 
 	quit := make(chan int)
 
 	var err error
 	var watcher *procwatch.Watcher
 	var client *rcon.Client
+	var router *chatcmd.Router
 	var cmdChan chan string
 	var stdout io.ReadCloser
 	var stderr io.ReadCloser
@@ -77,6 +119,9 @@ func do() error {
 			return err
 		}
 		glog.V(4).Infoln("Retrieving Procwatch Command Channel")
+		if useSched {
+			runScheduleReload(watcher)
+		}
 		cmdChan = watcher.GetCmdChannel()
 		glog.V(4).Infoln("Retrieving Procwatch Output Channels")
 		stderr, stdout = watcher.GetOutput()
@@ -96,20 +141,114 @@ func do() error {
 		if err != nil {
 			return err
 		}
-		if useSched {
+		if watcher != nil {
 			go pipeCommands(cmdChan, client, nil)
+			watcher.SetQuerier(client)
+		}
+		dispatcher, roster, err := runEvents(client, consoleIn, showChat, showEvents)
+		if err != nil {
+			return err
+		}
+		if usePingKick {
+			fmt.Println("PingKick is enabled")
+			if err = runPingKick(client); err != nil {
+				return err
+			}
+		}
+		if useChatMod {
+			fmt.Println("ChatMod is enabled")
+			if err = runChatMod(client, dispatcher, roster); err != nil {
+				return err
+			}
 		}
-		if showChat {
-			client.SetChatWriter(consoleIn)
+		if useChatCmd {
+			fmt.Println("ChatCmd is enabled")
+			if router, err = runChatCmd(client, dispatcher, roster, restartsOf(watcher)); err != nil {
+				return err
+			}
 		}
-		if showEvents {
-			client.SetEventWriter(consoleIn)
+		if useWelcome {
+			fmt.Println("Welcome Messages are enabled")
+			if err = runWelcome(client, dispatcher, roster, restartsOf(watcher)); err != nil {
+				return err
+			}
+		}
+		if useSlots {
+			fmt.Println("Reserved Slots are enabled")
+			if err = runSlots(client, dispatcher, roster); err != nil {
+				return err
+			}
+		}
+		if useVote {
+			fmt.Println("Voting is enabled")
+			if err = runVote(client, roster, router, watcher); err != nil {
+				return err
+			}
+		}
+		if useNamePolicy {
+			fmt.Println("NamePolicy is enabled")
+			if err = runNamePolicy(client, dispatcher); err != nil {
+				return err
+			}
+		}
+		if useIPFilter {
+			fmt.Println("IPFilter is enabled")
+			if err = runIPFilter(client, dispatcher); err != nil {
+				return err
+			}
+		}
+		if useGeoIP {
+			fmt.Println("GeoIP is enabled")
+			if err = runGeoIP(client, dispatcher, roster, router, consoleIn); err != nil {
+				return err
+			}
+		}
+		if useEvasion {
+			fmt.Println("Evasion Detection is enabled")
+			if err = runEvasion(client, dispatcher, roster, consoleIn); err != nil {
+				return err
+			}
+		}
+		if useWarnings {
+			fmt.Println("Warnings are enabled")
+			if err = runWarnings(client, dispatcher, roster, router, consoleIn); err != nil {
+				return err
+			}
+		}
+		if useAdminGuard {
+			fmt.Println("AdminGuard is enabled")
+			if err = runAdminGuard(dispatcher, consoleIn); err != nil {
+				return err
+			}
+		}
+		if useAnnouncer {
+			fmt.Println("Announcer is enabled")
+			if err = runAnnouncer(client, roster, watcher); err != nil {
+				return err
+			}
+		}
+		if useRelay {
+			fmt.Println("Chat Relay is enabled")
+			if err = runRelay(client, dispatcher, roster); err != nil {
+				return err
+			}
+		}
+		if useRotation {
+			fmt.Println("Mission Rotation is enabled")
+			if err = runRotation(client); err != nil {
+				return err
+			}
 		}
 		client.RunCommand("say -1 PlayNet GoRcon-ArmA Connected", nil)
 	} else {
 		fmt.Println("RCon is disabled")
 	}
 
+	if useAPI {
+		fmt.Println("API is enabled")
+		runAPI()
+	}
+
 	q := <-quit
 	if q == 1 {
 		return nil
@@ -130,8 +269,10 @@ func runWatcher(useSched, useWatch bool) (watcher *procwatch.Watcher, err error)
 		}
 		fmt.Println("\nScheduler is enabled")
 		fmt.Printf("\nScheduler Config: \n"+
-			"Path to scheduler.json: %v \n",
-			schedulerPath)
+			"Path to scheduler.json: %v \n"+
+			"Path to State: %v \n"+
+			"Catch-up Delay: %v seconds \n",
+			schedulerPath, cfg.GetString("scheduler.state"), cfg.GetInt("scheduler.catchUpDelay"))
 	} else {
 		schedulerEntity = &procwatch.Schedule{}
 	}
@@ -152,6 +293,16 @@ func runWatcher(useSched, useWatch bool) (watcher *procwatch.Watcher, err error)
 		Schedule:     *schedulerEntity,
 		UseScheduler: useSched,
 		UseWatcher:   useWatch,
+		Timezone:     cfg.GetString("scheduler.timezone"),
+		StatePath:    cfg.GetString("scheduler.state"),
+		CatchUpDelay: time.Second * time.Duration(cfg.GetInt("scheduler.catchUpDelay")),
+		Shutdown: procwatch.Shutdown{
+			Enabled:     cfg.GetBool("watcher.shutdown.enabled"),
+			KickMessage: cfg.GetString("watcher.shutdown.kickMessage"),
+			KickDelay:   time.Second * time.Duration(cfg.GetInt("watcher.shutdown.kickDelay")),
+			ExitTimeout: time.Second * time.Duration(cfg.GetInt("watcher.shutdown.exitTimeout")),
+			KillTimeout: time.Second * time.Duration(cfg.GetInt("watcher.shutdown.killTimeout")),
+		},
 	}
 
 	watcher = procwatch.New(pwcfg)
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/beguid"
+	"github.com/playnet-public/gorcon-arma/slots"
+)
+
+//getGUIDs reads a list of BattlEye GUIDs or SteamID64s from the config and returns the GUIDs
+func getGUIDs(key string) []string {
+	var guids []string
+	for _, id := range cfg.GetStringSlice(key) {
+		if guid, ok := resolveGUID(key, id); ok {
+			guids = append(guids, guid)
+		}
+	}
+	return guids
+}
+
+//getMembers reads slots.members from the config resolving SteamID64s
+func getMembers() ([]slots.Member, error) {
+	var members []slots.Member
+	if err := cfg.UnmarshalKey("slots.members", &members); err != nil {
+		return nil, err
+	}
+	result := members[:0]
+	for _, m := range members {
+		if guid, ok := resolveGUID("slots.members", m.GUID); ok {
+			m.GUID = guid
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+//resolveGUID converts id to a GUID and links SteamID64s to their GUID in the player database
+func resolveGUID(key, id string) (string, bool) {
+	guid, steamID, err := beguid.Resolve(id)
+	if err != nil {
+		glog.Warningf("Ignoring %q in %v: %v", id, key, err)
+		return "", false
+	}
+	if steamID == "" {
+		return guid, true
+	}
+	db, err := getPlayerDB()
+	if err == nil {
+		err = db.LinkSteamID(guid, steamID)
+	}
+	if err != nil {
+		glog.Warningf("Could not link SteamID %v to GUID %v: %v", steamID, guid, err)
+	}
+	return guid, true
+}
+
+//runGUID prints the BattlEye GUID of every given SteamID64
+func runGUID(ids []string) error {
+	if len(ids) == 0 {
+		return errors.New("usage: gorcon-arma guid <steamid64> [steamid64...]")
+	}
+	for _, id := range ids {
+		sid, err := beguid.ParseSteamID(id)
+		if err != nil {
+			return fmt.Errorf("%v: %v", id, err)
+		}
+		fmt.Printf("%v %v\n", id, beguid.FromSteamID(sid))
+	}
+	return nil
+}
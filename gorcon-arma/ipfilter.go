@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/ipfilter"
+)
+
+func runIPFilter(client *rcon.Client, dispatcher *events.Dispatcher) error {
+	ifcfg := ipfilter.Cfg{
+		Blocklists: cfg.GetStringSlice("ipfilter.blocklists"),
+		Allowlists: cfg.GetStringSlice("ipfilter.allowlists"),
+		Reason:     cfg.GetString("ipfilter.reason"),
+		Reload:     time.Second * time.Duration(cfg.GetInt("ipfilter.reload")),
+	}
+	fmt.Printf("\nIPFilter Config: \n"+
+		"Blocklists: %v \n"+
+		"Allowlists: %v \n\n",
+		ifcfg.Blocklists, ifcfg.Allowlists)
+	filter, err := ipfilter.New(ifcfg, client, getAuditLog())
+	if err != nil {
+		return err
+	}
+	go filter.Watch()
+	dispatcher.OnConnect(filter.HandleConnect)
+	return nil
+}
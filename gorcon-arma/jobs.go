@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/api"
+	"github.com/playnet-public/gorcon-arma/procwatch"
+)
+
+//registerJobs adds the endpoints listing and controlling the scheduled jobs
+func registerJobs(server *api.Server, watcher *procwatch.Watcher) {
+	server.Handle("/schedule/jobs", func(r *http.Request) (interface{}, error) {
+		return watcher.Jobs(), nil
+	}, "GET")
+	controls := map[string]func(id string) error{
+		"trigger": watcher.Trigger,
+		"pause":   watcher.Pause,
+		"resume":  watcher.Resume,
+		"skip":    watcher.SkipNext,
+	}
+	for name, control := range controls {
+		name, control := name, control
+		server.Handle("/schedule/jobs/"+name, func(r *http.Request) (interface{}, error) {
+			id := r.FormValue("id")
+			if id == "" && (name == "trigger" || name == "skip") {
+				return nil, api.BadRequest("id is required")
+			}
+			if err := control(id); err != nil {
+				if err == procwatch.ErrJobNotFound {
+					return nil, api.Error{Status: http.StatusNotFound, Message: err.Error()}
+				}
+				return nil, err
+			}
+			return watcher.Jobs(), nil
+		}, "POST")
+	}
+}
+
+//runJobs lists or controls the scheduled jobs of a running instance through its API
+func runJobs(args []string) error {
+	usage := errors.New("usage: gorcon-arma jobs [list] | trigger|skip <id> | pause|resume [id]")
+	command := "list"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	id := ""
+	if len(args) > 1 {
+		id = args[1]
+	}
+	var path string
+	switch command {
+	case "list":
+		path = "/schedule/jobs"
+	case "trigger", "skip":
+		if id == "" {
+			return usage
+		}
+		fallthrough
+	case "pause", "resume":
+		path = "/schedule/jobs/" + command
+	default:
+		return usage
+	}
+	cfg = getConfig()
+	if !cfg.GetBool("api.enabled") {
+		return errors.New("the api section of config.json has to be enabled")
+	}
+	method, form := "POST", url.Values{"id": {id}}
+	if command == "list" {
+		method, form = "GET", nil
+	}
+	var list procwatch.JobList
+	if err := callAPI(method, path, form, &list); err != nil {
+		return err
+	}
+	printJobs(list)
+	if id == "" && command != "list" {
+		fmt.Printf("\nScheduler %sd\n", command)
+	}
+	return nil
+}
+
+//callAPI sends a request to the API of the running instance and decodes the json response into result
+//form is sent as query of GET requests and as body otherwise
+func callAPI(method, path string, form url.Values, result interface{}) error {
+	address := "http://" + cfg.GetString("api.listen") + path
+	var body string
+	if method == "GET" {
+		if len(form) > 0 {
+			address += "?" + form.Encode()
+		}
+	} else {
+		body = form.Encode()
+	}
+	req, err := http.NewRequest(method, address, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if token := cfg.GetString("api.token"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{Timeout: time.Second * 10}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(content, &e) == nil && e.Error != "" {
+			return errors.New(e.Error)
+		}
+		return fmt.Errorf("API returned %v", resp.Status)
+	}
+	return json.Unmarshal(content, result)
+}
+
+//printJobs prints the jobs as table
+func printJobs(list procwatch.JobList) {
+	if list.Paused {
+		fmt.Printf("Scheduler paused, no job runs until it is resumed\n\n")
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSCHEDULE\tNEXT\tLAST RUN\tOUTCOME\tSTATE")
+	for _, info := range list.Jobs {
+		state := "active"
+		switch {
+		case list.Paused:
+			state = "scheduler paused"
+		case info.Paused:
+			state = "paused"
+		case info.SkipNext:
+			state = "skip next"
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\n",
+			info.ID, info.Description, formatTime(info.Next), formatTime(info.Prev), info.Outcome, state)
+	}
+	tw.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
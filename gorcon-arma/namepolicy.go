@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/namepolicy"
+)
+
+func runNamePolicy(client *rcon.Client, dispatcher *events.Dispatcher) error {
+	db, err := getPlayerDB()
+	if err != nil {
+		return err
+	}
+	members, err := getMembers()
+	if err != nil {
+		return err
+	}
+	npcfg := namepolicy.Cfg{
+		Words:      cfg.GetStringSlice("namepolicy.words"),
+		MinLength:  cfg.GetInt("namepolicy.minLength"),
+		MaxLength:  cfg.GetInt("namepolicy.maxLength"),
+		Disallowed: cfg.GetString("namepolicy.disallowed"),
+		ClanTag: namepolicy.ClanTag{
+			Pattern:     cfg.GetString("namepolicy.clanTag.pattern"),
+			MembersOnly: cfg.GetBool("namepolicy.clanTag.membersOnly"),
+			Required:    cfg.GetBool("namepolicy.clanTag.required"),
+		},
+		Admins:     getGUIDs("admins"),
+		AdminNames: cfg.GetStringSlice("namepolicy.adminNames"),
+		Message:    cfg.GetString("namepolicy.message"),
+	}
+	for _, m := range members {
+		npcfg.Members = append(npcfg.Members, m.GUID)
+	}
+	fmt.Printf("\nNamePolicy Config: \n"+
+		"Length: %v-%v \n"+
+		"Disallowed Characters: %v \n"+
+		"Clan Tag: %v \n\n",
+		npcfg.MinLength, npcfg.MaxLength, npcfg.Disallowed, npcfg.ClanTag.Pattern)
+	policy, err := namepolicy.New(npcfg, client, db)
+	if err != nil {
+		return err
+	}
+	dispatcher.OnConnect(policy.HandleConnect)
+	dispatcher.OnGUIDVerified(policy.HandleGUIDVerified)
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/pingkick"
+)
+
+func runPingKick(client *rcon.Client) error {
+	var periods []pingkick.Period
+	if err := cfg.UnmarshalKey("pingkick.periods", &periods); err != nil {
+		return err
+	}
+	pkcfg := pingkick.Cfg{
+		Interval:  time.Second * time.Duration(cfg.GetInt("pingkick.interval")),
+		MaxPing:   cfg.GetInt("pingkick.maxPing"),
+		Samples:   cfg.GetInt("pingkick.samples"),
+		Warning:   cfg.GetString("pingkick.warning"),
+		Reason:    cfg.GetString("pingkick.reason"),
+		Whitelist: getGUIDs("pingkick.whitelist"),
+		Periods:   periods,
+	}
+	fmt.Printf("\nPingKick Config: \n"+
+		"Max Ping: %v \n"+
+		"Samples: %v \n"+
+		"Periods: %v \n\n",
+		pkcfg.MaxPing, pkcfg.Samples, pkcfg.Periods)
+	pingkick.New(pkcfg, client).Start()
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/relay"
+)
+
+//remoteServer is a linked server the chat is relayed to
+type remoteServer struct {
+	Tag      string
+	IP       string
+	Port     string
+	Password string
+}
+
+func runRelay(client *rcon.Client, dispatcher *events.Dispatcher, roster *players.Roster) error {
+	db, err := getPlayerDB()
+	if err != nil {
+		return err
+	}
+	var remotes []remoteServer
+	if err := cfg.UnmarshalKey("relay.servers", &remotes); err != nil {
+		return err
+	}
+	rcfg := relay.Cfg{
+		Channels: cfg.GetStringSlice("relay.channels"),
+		Format:   cfg.GetString("relay.format"),
+		Command:  cfg.GetString("relay.command"),
+	}
+	local := &relay.Server{Tag: cfg.GetString("relay.tag"), Client: client, Roster: roster}
+	servers := []*relay.Server{local}
+	dispatchers := []*events.Dispatcher{dispatcher}
+	for _, r := range remotes {
+		fmt.Printf("\nRelay Server [%v]: %v:%v \n", r.Tag, r.IP, r.Port)
+		remoteDispatcher := events.NewDispatcher()
+		remote, online, err := connectRemote(r, remoteDispatcher)
+		if err != nil {
+			return err
+		}
+		servers = append(servers, &relay.Server{
+			Tag:    r.Tag,
+			Client: remote,
+			Roster: trackRoster(remote, remoteDispatcher),
+			Online: online,
+		})
+		dispatchers = append(dispatchers, remoteDispatcher)
+	}
+	fmt.Printf("\nRelay Config: \n"+
+		"Tag: %v \n"+
+		"Channels: %v \n\n",
+		local.Tag, rcfg.Channels)
+	rel, err := relay.New(rcfg, db, servers...)
+	if err != nil {
+		return err
+	}
+	for i, d := range dispatchers {
+		d.OnChat(rel.Handler(servers[i]))
+	}
+	return nil
+}
+
+//connectRemote connects to a linked server in the background using the keep alive settings of the managed server
+//online reports whether the server was reached, so an unreachable server does not hold up the startup
+func connectRemote(r remoteServer, dispatcher *events.Dispatcher) (client *rcon.Client, online func() bool, err error) {
+	udpadr, err := net.ResolveUDPAddr("udp", r.IP+":"+r.Port)
+	if err != nil {
+		return nil, nil, err
+	}
+	client = rcon.New(rcon.Config{
+		Addr:               udpadr,
+		Password:           r.Password,
+		KeepAliveTimer:     cfg.GetInt("arma.keepAliveTimer"),
+		KeepAliveTolerance: cfg.GetInt64("arma.keepAliveTolerance"),
+	})
+	client.SetChatWriter(dispatcher)
+	client.SetEventWriter(dispatcher)
+	var connected int32
+	go func() {
+		client.WatcherLoop()
+		atomic.StoreInt32(&connected, 1)
+		glog.Infof("Relay: connected to [%v] %v:%v", r.Tag, r.IP, r.Port)
+	}()
+	return client, func() bool { return atomic.LoadInt32(&connected) == 1 }, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/rotation"
+)
+
+func runRotation(client *rcon.Client) error {
+	var missions []rotation.Mission
+	if err := cfg.UnmarshalKey("rotation.missions", &missions); err != nil {
+		return err
+	}
+	var warnings []int
+	if err := cfg.UnmarshalKey("rotation.warnings", &warnings); err != nil {
+		return err
+	}
+	rcfg := rotation.Cfg{
+		Missions:    missions,
+		Weighted:    cfg.GetBool("rotation.weighted"),
+		Switch:      cfg.GetStringSlice("rotation.switch"),
+		Timezone:    cfg.GetString("scheduler.timezone"),
+		OnEnd:       cfg.GetBool("rotation.onEnd"),
+		Check:       time.Second * time.Duration(cfg.GetInt("rotation.check")),
+		Grace:       time.Second * time.Duration(cfg.GetInt("rotation.grace")),
+		Warnings:    warnings,
+		Message:     cfg.GetString("rotation.message"),
+		NextMessage: cfg.GetString("rotation.nextMessage"),
+	}
+	fmt.Printf("\nRotation Config: \n"+
+		"Missions: %v \n"+
+		"Weighted: %v \n"+
+		"Switch: %v \n"+
+		"On Mission End: %v \n"+
+		"Warnings: %v \n\n",
+		len(rcfg.Missions), rcfg.Weighted, rcfg.Switch, rcfg.OnEnd, rcfg.Warnings)
+	r, err := rotation.New(rcfg, client)
+	if err != nil {
+		return err
+	}
+	if err := r.Validate(); err == rotation.ErrNoMissions {
+		return err
+	} else if err != nil {
+		glog.Warningln(err)
+	}
+	fmt.Println("Upcoming Mission:", r.Upcoming())
+	go r.Run()
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/procwatch"
+	"github.com/spf13/viper"
+)
+
+//runScheduleReload reloads the schedule on file changes, SIGHUP and API calls
+func runScheduleReload(watcher *procwatch.Watcher) {
+	path := procwatch.SchedulePath(cfg.GetString("scheduler.path"))
+	reload := cfg.GetInt("scheduler.reload")
+	fmt.Printf("\nSchedule Reload: \n"+
+		"Check Interval: %v seconds (0 = disabled) \n\n",
+		reload)
+	if reload > 0 {
+		go watcher.WatchSchedule(path, time.Second*time.Duration(reload))
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			glog.Infoln("Received SIGHUP, reloading", path)
+			if _, err := watcher.ReloadFile(path); err != nil {
+				glog.Errorln("Keeping current Schedule:", err)
+			}
+		}
+	}()
+
+	if server := getAPI(); server != nil {
+		server.Handle("/schedule/reload", func(r *http.Request) (interface{}, error) {
+			return watcher.ReloadFile(path)
+		}, "POST")
+		registerJobs(server, watcher)
+	}
+}
+
+//runSchedule handles the schedule subcommands, they neither connect to RCon nor start the watcher
+func runSchedule(args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return errors.New("usage: gorcon-arma schedule check [-n count] [-timezone name] [schedule.json]")
+	}
+	cfg = viper.New()
+	cfg.SetConfigName("config")
+	cfg.AddConfigPath(".")
+	if err := cfg.ReadInConfig(); err != nil {
+		glog.V(1).Infoln("Checking Schedule without Config:", err)
+	}
+	cfg.SetDefault("scheduler.path", "schedule.json")
+
+	flags := flag.NewFlagSet("schedule check", flag.ContinueOnError)
+	count := flags.Int("n", 5, "amount of upcoming fire times listed per entry")
+	timezone := flags.String("timezone", cfg.GetString("scheduler.timezone"), "timezone of the schedule (default scheduler.timezone)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	path := procwatch.SchedulePath(cfg.GetString("scheduler.path"))
+	if flags.NArg() > 0 {
+		path = procwatch.SchedulePath(flags.Arg(0))
+	}
+	return checkSchedule(path, *timezone, cfg.GetBool("watcher.enabled"), *count)
+}
+
+//checkSchedule prints a description and the upcoming fire times of every entry in the schedule at path
+//Entries are validated like the running instance does with or without watcher
+func checkSchedule(path procwatch.SchedulePath, timezone string, useWatcher bool, count int) error {
+	schedule, err := path.Parse()
+	if err != nil {
+		return fmt.Errorf("%v: %v", path, err)
+	}
+	previews, err := schedule.Preview(timezone, useWatcher, count, time.Now())
+	if err != nil {
+		return err
+	}
+	if timezone == "" {
+		timezone = "Local"
+	}
+	invalid := 0
+	for i, p := range previews {
+		fmt.Printf("#%d %v\n", i+1, p.Entry)
+		if p.Err != nil {
+			invalid++
+			fmt.Printf("   Error: %v\n\n", p.Err)
+			continue
+		}
+		fmt.Printf("   Action: %v\n", p.Action)
+		if p.Conditions != "" {
+			fmt.Printf("   Conditions: %v\n", p.Conditions)
+		}
+		if p.Entry.CatchUp != "" {
+			fmt.Printf("   Catch-up: %v\n", p.Entry.CatchUp)
+		}
+		if len(p.Next) == 0 {
+			fmt.Println("   Next: never (in the past)")
+		}
+		for _, t := range p.Next {
+			fmt.Printf("   Next: %v\n", t.Format("Mon 2006-01-02 15:04:05 MST"))
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%v: %d entries, %d invalid (timezone %v)\n", path, len(previews), invalid, timezone)
+	if invalid > 0 {
+		return fmt.Errorf("%v contains %d invalid entries", path, invalid)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/slots"
+)
+
+func runSlots(client *rcon.Client, dispatcher *events.Dispatcher, roster *players.Roster) error {
+	members, err := getMembers()
+	if err != nil {
+		return err
+	}
+	scfg := slots.Cfg{
+		Limit:   cfg.GetInt("slots.limit"),
+		Members: members,
+		Admins:  getGUIDs("admins"),
+		Message: cfg.GetString("slots.message"),
+		DryRun:  cfg.GetBool("slots.dryRun"),
+	}
+	fmt.Printf("\nSlots Config: \n"+
+		"Public Slots: %v \n"+
+		"Members: %v \n"+
+		"Dry-Run: %v \n\n",
+		scfg.Limit, len(scfg.Members), scfg.DryRun)
+	dispatcher.OnGUIDVerified(slots.New(scfg, client, roster).HandleGUIDVerified)
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/chatcmd"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/procwatch"
+	"github.com/playnet-public/gorcon-arma/vote"
+)
+
+func runVote(client *rcon.Client, roster *players.Roster, router *chatcmd.Router, watcher *procwatch.Watcher) error {
+	if router == nil {
+		return errors.New("voting requires chatcmd to be enabled")
+	}
+	vcfg := vote.Cfg{
+		Window:     time.Second * time.Duration(cfg.GetInt("vote.window")),
+		Quorum:     cfg.GetInt("vote.quorum"),
+		Percent:    cfg.GetInt("vote.percent"),
+		Cooldown:   time.Second * time.Duration(cfg.GetInt("vote.cooldown")),
+		Admins:     getGUIDs("admins"),
+		KickReason: cfg.GetString("vote.kickReason"),
+	}
+	fmt.Printf("\nVote Config: \n"+
+		"Window: %v \n"+
+		"Quorum: %v \n"+
+		"Percent: %v \n"+
+		"Cooldown: %v \n\n",
+		vcfg.Window, vcfg.Quorum, vcfg.Percent, vcfg.Cooldown)
+	var restarter vote.Restarter
+	if watcher != nil {
+		restarter = watcher
+	}
+	vote.New(vcfg, client, roster, restarter).Register(router)
+	return nil
+}
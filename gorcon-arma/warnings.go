@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/api"
+	"github.com/playnet-public/gorcon-arma/beguid"
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/chatcmd"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/warnings"
+)
+
+func runWarnings(client *rcon.Client, dispatcher *events.Dispatcher, roster *players.Roster, router *chatcmd.Router, console io.Writer) error {
+	db, err := getPlayerDB()
+	if err != nil {
+		return err
+	}
+	wcfg := warnings.Cfg{
+		Message: cfg.GetString("warnings.message"),
+		Admins:  getGUIDs("admins"),
+	}
+	if err := cfg.UnmarshalKey("warnings.thresholds", &wcfg.Thresholds); err != nil {
+		return err
+	}
+	fmt.Printf("\nWarnings Config: \n"+
+		"Message: %v \n"+
+		"Thresholds: %+v \n\n",
+		wcfg.Message, wcfg.Thresholds)
+	if !cfg.GetBool("arma.showEvents") {
+		console = nil
+	}
+	manager, err := warnings.New(wcfg, client, roster, db, getAuditLog(), console)
+	if err != nil {
+		return err
+	}
+	dispatcher.OnGUIDVerified(manager.HandleGUIDVerified)
+	if router != nil {
+		manager.Register(router)
+	}
+	if server := getAPI(); server != nil {
+		server.Handle("/players/remarks", func(r *http.Request) (interface{}, error) {
+			guid, _, err := beguid.Resolve(r.FormValue("id"))
+			if err != nil {
+				return nil, api.BadRequest(err.Error())
+			}
+			return db.Get(guid), nil
+		}, "GET")
+		server.Handle("/players/warn", remarkHandler(manager.Warn), "POST")
+		server.Handle("/players/note", remarkHandler(manager.Note), "POST")
+	}
+	return nil
+}
+
+//remarkHandler adds the text of a request as warning or note to the player identified by id
+func remarkHandler(add func(guid, author, text string) (playerdb.Record, error)) api.HandlerFunc {
+	return func(r *http.Request) (interface{}, error) {
+		guid, _, err := beguid.Resolve(r.FormValue("id"))
+		if err != nil {
+			return nil, api.BadRequest(err.Error())
+		}
+		author := r.FormValue("author")
+		if author == "" {
+			author = "API"
+		}
+		res, err := add(guid, author, r.FormValue("text"))
+		if err == warnings.ErrEmpty {
+			return nil, api.BadRequest(err.Error())
+		}
+		return res, err
+	}
+}
+
+//runRemarks adds or lists warnings and notes through the API of the running instance
+//The player database is only written directly if the API is enabled but no instance answers, a running instance
+//would overwrite the changes with its own copy. Sanctions for warnings added this way are applied when the player joins the next time
+func runRemarks(args []string) error {
+	if len(args) < 2 || (args[0] != "notes" && len(args) < 3) {
+		return errors.New("usage: gorcon-arma warn|note <guid|steamid64> <text> or gorcon-arma notes <guid|steamid64>")
+	}
+	cfg = getConfig()
+	guid, _, err := beguid.Resolve(args[1])
+	if err != nil {
+		return fmt.Errorf("%v: %v", args[1], err)
+	}
+	text := strings.Join(args[2:], " ")
+	if cfg.GetBool("api.enabled") {
+		var record playerdb.Record
+		err := callRemarksAPI(args[0], guid, text, &record)
+		if _, unreachable := err.(*url.Error); !unreachable {
+			if err != nil {
+				return err
+			}
+			fmt.Println(warnings.Summary(record))
+			return nil
+		}
+		glog.V(1).Infoln("API not reachable, using the Player Database directly:", err)
+	} else if args[0] != "notes" {
+		return errors.New("the api section has to be enabled to add warnings or notes, a running instance would overwrite changes made to the player database")
+	}
+	db, err := getPlayerDB()
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "warn":
+		_, err = db.Warn(guid, "CLI", text)
+	case "note":
+		_, err = db.Note(guid, "CLI", text)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(warnings.Summary(db.Get(guid)))
+	return nil
+}
+
+//callRemarksAPI runs the remark command through the API and decodes the resulting Record
+func callRemarksAPI(command, guid, text string, record *playerdb.Record) error {
+	if command == "notes" {
+		return callAPI("GET", "/players/remarks", url.Values{"id": {guid}}, record)
+	}
+	return callAPI("POST", "/players/"+command, url.Values{"id": {guid}, "text": {text}, "author": {"CLI"}}, record)
+}
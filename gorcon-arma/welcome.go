@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	rcon "github.com/playnet-public/gorcon-arma/bercon"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/procwatch"
+	"github.com/playnet-public/gorcon-arma/welcome"
+)
+
+func runWelcome(client *rcon.Client, dispatcher *events.Dispatcher, roster *players.Roster, restarts welcome.Restarts) error {
+	db, err := getPlayerDB()
+	if err != nil {
+		return err
+	}
+	wcfg := welcome.Cfg{
+		First:     cfg.GetStringSlice("welcome.first"),
+		Returning: cfg.GetStringSlice("welcome.returning"),
+		Rules:     cfg.GetString("welcome.rules"),
+		Delay:     time.Second * time.Duration(cfg.GetInt("welcome.delay")),
+	}
+	fmt.Printf("\nWelcome Config: \n"+
+		"First Visit: %v \n"+
+		"Returning: %v \n"+
+		"Delay: %v \n\n",
+		wcfg.First, wcfg.Returning, wcfg.Delay)
+	w := welcome.New(wcfg, client, roster, db, restarts)
+	dispatcher.OnGUIDVerified(w.HandleGUIDVerified)
+	return nil
+}
+
+//restartsOf returns the watcher as restart source if the scheduler is running
+func restartsOf(watcher *procwatch.Watcher) welcome.Restarts {
+	if watcher == nil || !cfg.GetBool("scheduler.enabled") {
+		return nil
+	}
+	return watcher
+}
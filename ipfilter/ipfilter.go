@@ -0,0 +1,266 @@
+package ipfilter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/audit"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+)
+
+//Cfg contains all data required by the Filter
+//Blocklists and Allowlists are file paths or glob patterns
+type Cfg struct {
+	Blocklists []string
+	Allowlists []string
+	Reason     string
+	Reload     time.Duration
+}
+
+//Config is the Interface providing Configs for the Filter
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to kick players
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+type entry struct {
+	net    *net.IPNet
+	source string
+}
+
+//Filter kicks players connecting from blocked networks unless they are allowed
+type Filter struct {
+	client     Client
+	audit      *audit.Log
+	blockPaths []string
+	allowPaths []string
+	reason     string
+	reload     time.Duration
+
+	lists struct {
+		sync.RWMutex
+		block   []entry
+		allow   []entry
+		modTime map[string]time.Time
+	}
+}
+
+//New creates a Filter with given Config and loads all lists
+func New(c Config, client Client, auditLog *audit.Log) (*Filter, error) {
+	cfg := c.GetConfig()
+	if cfg.Reason == "" {
+		cfg.Reason = "Your network is not allowed on this server"
+	}
+	if cfg.Reload == 0 {
+		cfg.Reload = time.Minute
+	}
+	f := &Filter{
+		client:     client,
+		audit:      auditLog,
+		blockPaths: cfg.Blocklists,
+		allowPaths: cfg.Allowlists,
+		reason:     cfg.Reason,
+		reload:     cfg.Reload,
+	}
+	if _, err := f.Load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+//Watch reloads the lists whenever one of the files changed
+func (f *Filter) Watch() {
+	for {
+		glog.V(10).Infoln("Looping in IPFilter Watch")
+		time.Sleep(f.reload)
+		changed, err := f.changed()
+		if err != nil {
+			glog.Errorln("IPFilter could not check Lists:", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if _, err := f.Load(); err != nil {
+			glog.Errorln("IPFilter could not reload Lists, keeping previous ones:", err)
+		}
+	}
+}
+
+//Load (re)reads all lists and returns the amount of loaded networks
+func (f *Filter) Load() (int, error) {
+	modTime := make(map[string]time.Time)
+	block, err := loadLists(f.blockPaths, modTime)
+	if err != nil {
+		return 0, err
+	}
+	allow, err := loadLists(f.allowPaths, modTime)
+	if err != nil {
+		return 0, err
+	}
+	f.lists.Lock()
+	f.lists.block = block
+	f.lists.allow = allow
+	f.lists.modTime = modTime
+	f.lists.Unlock()
+	glog.V(1).Infof("IPFilter loaded %v blocked and %v allowed Networks", len(block), len(allow))
+	return len(block) + len(allow), nil
+}
+
+//Check returns the source list blocking ip or an empty string if ip is not blocked
+func (f *Filter) Check(ip string) string {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return ""
+	}
+	f.lists.RLock()
+	defer f.lists.RUnlock()
+	for _, e := range f.lists.allow {
+		if e.net.Contains(addr) {
+			return ""
+		}
+	}
+	for _, e := range f.lists.block {
+		if e.net.Contains(addr) {
+			return e.source
+		}
+	}
+	return ""
+}
+
+//HandleConnect kicks the connecting player if his IP is blocked
+func (f *Filter) HandleConnect(e events.Connect) {
+	source := f.Check(e.IP)
+	if source == "" {
+		return
+	}
+	glog.Infof("IPFilter: kicking %v (%v listed in %v)", e.Name, e.IP, source)
+	text := message.Render(f.reason, message.Vars{"name": e.Name, "ip": e.IP})
+	f.client.RunCommand(fmt.Sprintf("kick %d %s", e.Number, text), nil)
+	f.audit.Record(audit.Entry{
+		Source: "ipfilter",
+		Action: "kick",
+		Name:   e.Name,
+		IP:     e.IP,
+		Reason: "listed in " + source,
+	})
+}
+
+//changed returns whether any list file was added, removed or modified since the last load
+func (f *Filter) changed() (bool, error) {
+	files, err := expand(append(append([]string{}, f.blockPaths...), f.allowPaths...))
+	if err != nil {
+		return false, err
+	}
+	f.lists.RLock()
+	defer f.lists.RUnlock()
+	if len(files) != len(f.lists.modTime) {
+		return true, nil
+	}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return true, nil
+		}
+		if !info.ModTime().Equal(f.lists.modTime[file]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func expand(patterns []string) ([]string, error) {
+	var files []string
+	for _, p := range patterns {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+func loadLists(patterns []string, modTime map[string]time.Time) ([]entry, error) {
+	files, err := expand(patterns)
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTime[file] = info.ModTime()
+		nets, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range nets {
+			entries = append(entries, entry{net: n, source: filepath.Base(file)})
+		}
+	}
+	return entries, nil
+}
+
+//loadFile reads one IP or CIDR range per line, lines starting with # are comments
+func loadFile(file string) ([]*net.IPNet, error) {
+	fh, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	var nets []*net.IPNet
+	scanner := bufio.NewScanner(fh)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = strings.TrimSpace(text[:i])
+		}
+		if text == "" {
+			continue
+		}
+		n, err := ParseNet(text)
+		if err != nil {
+			glog.Warningf("IPFilter ignoring %v:%v: %v", file, line, err)
+			continue
+		}
+		nets = append(nets, n)
+	}
+	return nets, scanner.Err()
+}
+
+//ParseNet parses an IP or CIDR range, single IPs result in a host network
+func ParseNet(text string) (*net.IPNet, error) {
+	if !strings.Contains(text, "/") {
+		ip := net.ParseIP(text)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP %q", text)
+		}
+		if ip.To4() != nil {
+			return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, n, err := net.ParseCIDR(text)
+	return n, err
+}
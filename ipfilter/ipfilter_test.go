@@ -0,0 +1,99 @@
+package ipfilter
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/events"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+func writeFile(t *testing.T, file, content string) {
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Filter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ipfilter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFile(t, filepath.Join(dir, "vpn.txt"), "# VPN Provider\n10.0.0.0/8\n192.168.1.5 # single host\ninvalid\n")
+	writeFile(t, filepath.Join(dir, "allow.list"), "10.1.2.0/24\n")
+
+	client := &fakeClient{}
+	f, err := New(Cfg{
+		Blocklists: []string{filepath.Join(dir, "*.txt")},
+		Allowlists: []string{filepath.Join(dir, "allow.list")},
+		Reason:     "Blocked {ip}",
+	}, client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		ip       string
+		expected string
+	}{
+		{"10.20.30.40", "vpn.txt"},
+		{"10.1.2.3", ""},
+		{"192.168.1.5", "vpn.txt"},
+		{"192.168.1.6", ""},
+		{"invalid", ""},
+	}
+	for _, v := range tests {
+		if res := f.Check(v.ip); res != v.expected {
+			t.Errorf("%v Expected: %q Got: %q", v.ip, v.expected, res)
+		}
+	}
+
+	f.HandleConnect(events.Connect{Number: 3, Name: "Steve", IP: "10.20.30.40"})
+	f.HandleConnect(events.Connect{Number: 4, Name: "Kevin", IP: "127.0.0.1"})
+	expected := []string{"kick 3 Blocked 10.20.30.40"}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_changed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ipfilter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "block.txt")
+	writeFile(t, file, "10.0.0.0/8\n")
+	f, err := New(Cfg{Blocklists: []string{filepath.Join(dir, "*.txt")}}, &fakeClient{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := f.changed(); changed {
+		t.Error("Expected no change")
+	}
+	writeFile(t, file, "172.16.0.0/12\n")
+	os.Chtimes(file, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	if changed, _ := f.changed(); !changed {
+		t.Error("Expected modified file to be detected")
+	}
+	f.Load()
+	if f.Check("172.16.1.1") == "" || f.Check("10.0.0.1") != "" {
+		t.Error("Expected reloaded List to be active")
+	}
+	writeFile(t, filepath.Join(dir, "new.txt"), "")
+	if changed, _ := f.changed(); !changed {
+		t.Error("Expected new file to be detected")
+	}
+}
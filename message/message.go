@@ -0,0 +1,39 @@
+package message
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//Vars maps placeholder names to their values
+type Vars map[string]interface{}
+
+//Render replaces all {name} placeholders in tpl with the matching vars
+func Render(tpl string, vars Vars) string {
+	if len(vars) == 0 {
+		return tpl
+	}
+	pairs := make([]string, 0, len(vars)*2)
+	for k, v := range vars {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(tpl)
+}
+
+//Duration formats d in a human readable way like 1h 5m
+func Duration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	d = d / time.Minute * time.Minute
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh %dm", h, m)
+}
@@ -0,0 +1,109 @@
+package message
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_Render(t *testing.T) {
+	var tests = []struct {
+		tpl      string
+		vars     Vars
+		expected string
+	}{
+		{"Hello {name}", Vars{"name": "Steve"}, "Hello Steve"},
+		{"Ping {ping}ms > {max}ms", Vars{"ping": 300, "max": 250}, "Ping 300ms > 250ms"},
+		{"Unknown {foo}", Vars{"name": "Steve"}, "Unknown {foo}"},
+		{"No Vars", nil, "No Vars"},
+	}
+	for _, v := range tests {
+		if res := Render(v.tpl, v.vars); res != v.expected {
+			t.Error("Expected:", v.expected, "Got:", res)
+		}
+	}
+}
+
+func Test_Duration(t *testing.T) {
+	var tests = []struct {
+		d        time.Duration
+		expected string
+	}{
+		{time.Second * 42, "42s"},
+		{time.Minute*5 + time.Second*59, "5m"},
+		{time.Hour * 2, "2h"},
+		{time.Hour*26 + time.Minute*3, "26h 3m"},
+	}
+	for _, v := range tests {
+		if res := Duration(v.d); res != v.expected {
+			t.Error("Expected:", v.expected, "Got:", res)
+		}
+	}
+}
+
+func Test_Normalize(t *testing.T) {
+	var tests = []struct {
+		text     string
+		expected []string
+	}{
+		{"Hello World", []string{"hello", "world"}},
+		{"B4DW0RD", []string{"badword"}},
+		{"b a d word", []string{"bad", "word"}},
+		{"bаdwоrd", []string{"badword"}},
+		{"baaaaad!!", []string{"baaaaad"}},
+		{"I was a s s", []string{"i", "was", "ass"}},
+		{"ｂａｄ", []string{"bad"}},
+	}
+	for _, v := range tests {
+		if res := Normalize(v.text); !reflect.DeepEqual(res, v.expected) {
+			t.Errorf("%q Expected: %v Got: %v", v.text, v.expected, res)
+		}
+	}
+}
+
+func Test_Blocklist(t *testing.T) {
+	b := NewBlocklist([]string{"ass", "butt", "bad"})
+	var tests = []struct {
+		word     string
+		expected bool
+	}{
+		{"ass", true},
+		{"asssss", true},
+		{"butt", true},
+		{"baaaad", true},
+		{"bad", true},
+		{"as", false},
+		{"but", false},
+		{"bat", false},
+		{"badd", true},
+		{"class", false},
+		{"assassin", false},
+	}
+	for _, v := range tests {
+		if res := b.Contains(v.word); res != v.expected {
+			t.Errorf("%q Expected: %v Got: %v", v.word, v.expected, res)
+		}
+	}
+}
+
+func Test_BlocklistWithin(t *testing.T) {
+	b := NewBlocklist([]string{"nazi", "ass"})
+	var tests = []struct {
+		text     string
+		expected bool
+	}{
+		{"nazi", true},
+		{"tagnazi", true},
+		{"xxnaazixx", true},
+		{"nazikiller", true},
+		{"badass", true},
+		{"jason", false},
+		{"naz", false},
+		{"steve", false},
+	}
+	for _, v := range tests {
+		if res := b.Within(v.text); res != v.expected {
+			t.Errorf("%q Expected: %v Got: %v", v.text, v.expected, res)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package message
+
+import (
+	"strings"
+	"unicode"
+)
+
+//folding maps leetspeak and lookalike characters to their plain latin letter
+var folding = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '€': 'e', '£': 'l',
+	//accented latin
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ý': 'y', 'ÿ': 'y', 'ñ': 'n', 'ç': 'c', 'ß': 's',
+	//cyrillic and greek lookalikes
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's',
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x',
+}
+
+//Normalize lowercases text, folds leetspeak and lookalikes and returns the remaining words
+//Repeated letters are kept, use a Blocklist to match words like "baaad"
+func Normalize(text string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		if f, ok := folding[r]; ok {
+			r = f
+		}
+		//fullwidth forms like ｆｏｏ
+		if r >= 'ａ' && r <= 'ｚ' {
+			r = r - 'ａ' + 'a'
+		}
+		if !unicode.IsLetter(r) {
+			if unicode.IsSpace(r) {
+				flush()
+			}
+			continue
+		}
+		word = append(word, r)
+	}
+	flush()
+	return joinSpelled(words)
+}
+
+//joinSpelled merges runs of single letters like "b a d" into one word
+func joinSpelled(words []string) []string {
+	var result []string
+	var spelled string
+	for _, w := range words {
+		if len([]rune(w)) == 1 {
+			spelled += w
+			continue
+		}
+		if spelled != "" {
+			result = append(result, spelled)
+			spelled = ""
+		}
+		result = append(result, w)
+	}
+	if spelled != "" {
+		result = append(result, spelled)
+	}
+	return result
+}
+
+//Collapse removes repeated letters like "baaad" to "bad"
+func Collapse(w string) string {
+	var result []rune
+	var last rune
+	for _, r := range w {
+		if r != last {
+			result = append(result, r)
+		}
+		last = r
+	}
+	return string(result)
+}
+
+//Blocklist matches normalized words against blocked words
+//Letters may be repeated more often than in the blocked word but not less,
+//so "baaad" matches "bad" while "as" does not match "ass"
+type Blocklist map[string][]string
+
+//NewBlocklist returns a Blocklist of the normalized words
+func NewBlocklist(words []string) Blocklist {
+	b := make(Blocklist)
+	for _, w := range words {
+		key := Collapse(w)
+		b[key] = append(b[key], w)
+	}
+	return b
+}
+
+//Contains returns whether the normalized word matches a blocked word
+func (b Blocklist) Contains(word string) bool {
+	for _, blocked := range b[Collapse(word)] {
+		if covers(runs(word), runs(blocked)) {
+			return true
+		}
+	}
+	return false
+}
+
+//Within returns whether a blocked word occurs anywhere in the normalized text, like "nazi" in "xxnaazikiller"
+//Letters may be repeated more often than in the blocked word, as for Contains
+func (b Blocklist) Within(text string) bool {
+	textRuns := runs(text)
+	for _, list := range b {
+		for _, blocked := range list {
+			blockedRuns := runs(blocked)
+			for i := 0; i+len(blockedRuns) <= len(textRuns); i++ {
+				if covers(textRuns[i:i+len(blockedRuns)], blockedRuns) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+//run is a letter and how often it is repeated
+type run struct {
+	letter rune
+	count  int
+}
+
+func runs(w string) []run {
+	var result []run
+	for _, r := range w {
+		if n := len(result); n > 0 && result[n-1].letter == r {
+			result[n-1].count++
+			continue
+		}
+		result = append(result, run{letter: r, count: 1})
+	}
+	return result
+}
+
+//covers returns whether word repeats every letter at least as often as blocked, both having the same letters
+func covers(word, blocked []run) bool {
+	if len(word) != len(blocked) {
+		return false
+	}
+	for i := range word {
+		if word[i].letter != blocked[i].letter || word[i].count < blocked[i].count {
+			return false
+		}
+	}
+	return true
+}
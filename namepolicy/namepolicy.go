@@ -0,0 +1,197 @@
+package namepolicy
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+)
+
+//ClanTag restricts the use of the clan tag to members
+//MembersOnly forbids the tag for non members, Required forces members to wear it
+type ClanTag struct {
+	Pattern     string
+	MembersOnly bool
+	Required    bool
+}
+
+//Cfg contains all data required by the Policy
+type Cfg struct {
+	Words      []string
+	MinLength  int
+	MaxLength  int
+	Disallowed string
+	ClanTag    ClanTag
+	Members    []string
+	Admins     []string
+	AdminNames []string
+	Message    string
+}
+
+//Config is the Interface providing Configs for the Policy
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to kick players
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Policy kicks players whose name violates the configured rules
+type Policy struct {
+	client     Client
+	db         *playerdb.DB
+	words      message.Blocklist
+	minLength  int
+	maxLength  int
+	disallowed string
+	clanTag    *regexp.Regexp
+	tagMembers bool
+	tagForced  bool
+	members    map[string]bool
+	admins     []string
+	adminNames []string
+	message    string
+}
+
+//New creates a Policy with given Config, db provides the last known names of the admins
+func New(c Config, client Client, db *playerdb.DB) (*Policy, error) {
+	cfg := c.GetConfig()
+	if cfg.Message == "" {
+		cfg.Message = "Invalid name: {reason}"
+	}
+	p := &Policy{
+		client:     client,
+		db:         db,
+		minLength:  cfg.MinLength,
+		maxLength:  cfg.MaxLength,
+		disallowed: cfg.Disallowed,
+		tagMembers: cfg.ClanTag.MembersOnly,
+		tagForced:  cfg.ClanTag.Required,
+		members:    make(map[string]bool),
+		adminNames: cfg.AdminNames,
+		message:    cfg.Message,
+	}
+	var words []string
+	for _, w := range cfg.Words {
+		words = append(words, strings.Join(message.Normalize(w), ""))
+	}
+	p.words = message.NewBlocklist(words)
+	if cfg.ClanTag.Pattern != "" {
+		re, err := regexp.Compile(cfg.ClanTag.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clan tag pattern %q: %v", cfg.ClanTag.Pattern, err)
+		}
+		p.clanTag = re
+	}
+	for _, guid := range cfg.Members {
+		p.members[strings.ToLower(guid)] = true
+	}
+	for _, guid := range cfg.Admins {
+		guid = strings.ToLower(guid)
+		p.members[guid] = true
+		p.admins = append(p.admins, guid)
+	}
+	return p, nil
+}
+
+//HandleConnect checks the name of a connecting player
+func (p *Policy) HandleConnect(e events.Connect) {
+	if reason := p.check(e.Name); reason != "" {
+		p.kick(e.Number, e.Name, reason)
+	}
+}
+
+//HandleGUIDVerified checks the name against the players identity
+func (p *Policy) HandleGUIDVerified(e events.GUIDVerified) {
+	if reason := p.checkIdentity(e.Name, e.GUID); reason != "" {
+		p.kick(e.Number, e.Name, reason)
+	}
+}
+
+//check returns the violated rule of a name or an empty string
+func (p *Policy) check(name string) string {
+	length := utf8.RuneCountInString(strings.TrimSpace(name))
+	if p.minLength > 0 && length < p.minLength {
+		return fmt.Sprintf("at least %d characters required", p.minLength)
+	}
+	if p.maxLength > 0 && length > p.maxLength {
+		return fmt.Sprintf("at most %d characters allowed", p.maxLength)
+	}
+	if i := strings.IndexAny(name, p.disallowed); p.disallowed != "" && i >= 0 {
+		r, _ := utf8.DecodeRuneInString(name[i:])
+		return fmt.Sprintf("character %q is not allowed", r)
+	}
+	//names rarely contain spaces, so words are blocked anywhere in the name
+	if p.words.Within(strings.Join(message.Normalize(name), "")) {
+		return "inappropriate name"
+	}
+	return ""
+}
+
+//checkIdentity returns the violated rule of a name used by the given guid or an empty string
+func (p *Policy) checkIdentity(name, guid string) string {
+	member := p.members[guid]
+	if p.clanTag != nil {
+		tagged := p.clanTag.MatchString(name)
+		if tagged && !member && p.tagMembers {
+			return "clan tag is reserved for members"
+		}
+		if !tagged && member && p.tagForced {
+			return "members have to use the clan tag"
+		}
+	}
+	if p.impersonates(name, guid) {
+		return "impersonating an admin"
+	}
+	return ""
+}
+
+//impersonates returns whether name resembles the name of an admin other than guid
+func (p *Policy) impersonates(name, guid string) bool {
+	names := append([]string{}, p.adminNames...)
+	for _, admin := range p.admins {
+		if admin == guid {
+			return false
+		}
+		if p.db != nil {
+			if r := p.db.Get(admin); r.Name != "" {
+				names = append(names, r.Name)
+			}
+		}
+	}
+	player := compact(name)
+	for _, n := range names {
+		admin := compact(n)
+		if admin == "" {
+			continue
+		}
+		if player == admin || (len(admin) >= 4 && strings.Contains(player, admin)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) kick(number int, name, reason string) {
+	glog.Infof("NamePolicy: kicking %v (%v)", name, reason)
+	text := message.Render(p.message, message.Vars{"name": name, "reason": reason})
+	p.client.RunCommand(fmt.Sprintf("kick %d %s", number, text), nil)
+}
+
+//compact returns the normalized name without spaces and repeated letters
+func compact(name string) string {
+	return message.Collapse(strings.Join(message.Normalize(name), ""))
+}
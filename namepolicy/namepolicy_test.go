@@ -0,0 +1,98 @@
+package namepolicy
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/playnet-public/gorcon-arma/events"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+const (
+	adminGUID  = "0123456789abcdef0123456789abcdef"
+	memberGUID = "11111111111111111111111111111111"
+	publicGUID = "22222222222222222222222222222222"
+)
+
+func newPolicy(t *testing.T) (*Policy, *fakeClient) {
+	client := &fakeClient{}
+	p, err := New(Cfg{
+		Words:      []string{"badword", "nazi"},
+		MinLength:  3,
+		MaxLength:  20,
+		Disallowed: "<>@",
+		ClanTag:    ClanTag{Pattern: `^\[PN\]`, MembersOnly: true, Required: true},
+		Members:    []string{memberGUID},
+		Admins:     []string{adminGUID},
+		AdminNames: []string{"Kevin"},
+	}, client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, client
+}
+
+func Test_check(t *testing.T) {
+	p, _ := newPolicy(t)
+	var tests = []struct {
+		name     string
+		expected string
+	}{
+		{"Steve", ""},
+		{"Al", "at least 3 characters required"},
+		{"ThisNameIsWayTooLongForUs", "at most 20 characters allowed"},
+		{"<Steve>", "character '<' is not allowed"},
+		{"B4dW0rd", "inappropriate name"},
+		{"nazi", "inappropriate name"},
+		{"Nazi Killer", "inappropriate name"},
+		{"[TAG]Nazi", "inappropriate name"},
+		{"NaziKiller", "inappropriate name"},
+		{"xX_n4zi_Xx", "inappropriate name"},
+		{"Nadine", ""},
+	}
+	for _, v := range tests {
+		if res := p.check(v.name); res != v.expected {
+			t.Errorf("%q Expected: %q Got: %q", v.name, v.expected, res)
+		}
+	}
+}
+
+func Test_checkIdentity(t *testing.T) {
+	p, _ := newPolicy(t)
+	var tests = []struct {
+		name     string
+		guid     string
+		expected string
+	}{
+		{"[PN] Member", memberGUID, ""},
+		{"Member", memberGUID, "members have to use the clan tag"},
+		{"[PN] Fake", publicGUID, "clan tag is reserved for members"},
+		{"Steve", publicGUID, ""},
+		{"K3vin", publicGUID, "impersonating an admin"},
+		{"xX Kevin Xx", publicGUID, "impersonating an admin"},
+		{"[PN] Kevin", adminGUID, ""},
+	}
+	for _, v := range tests {
+		if res := p.checkIdentity(v.name, v.guid); res != v.expected {
+			t.Errorf("%q Expected: %q Got: %q", v.name, v.expected, res)
+		}
+	}
+}
+
+func Test_HandleConnect(t *testing.T) {
+	p, client := newPolicy(t)
+	p.HandleConnect(events.Connect{Number: 5, Name: "Al"})
+	p.HandleConnect(events.Connect{Number: 6, Name: "Steve"})
+	expected := []string{"kick 5 Invalid name: at least 3 characters required"}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
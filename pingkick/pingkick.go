@@ -0,0 +1,205 @@
+package pingkick
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Period overrides the ping limits during a time of day (From/To as HH:MM)
+type Period struct {
+	From    string
+	To      string
+	MaxPing int
+	Samples int
+}
+
+//Cfg contains all data required by the PingKick
+type Cfg struct {
+	Interval  time.Duration
+	MaxPing   int
+	Samples   int
+	Warning   string
+	Reason    string
+	Whitelist []string
+	Periods   []Period
+}
+
+//Config is the Interface providing Configs for the PingKick
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to watch and kick players
+type Client interface {
+	players.Querier
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+type period struct {
+	from    int
+	to      int
+	maxPing int
+	samples int
+}
+
+//PingKick kicks players exceeding the ping limit for several samples in a row
+type PingKick struct {
+	client    Client
+	interval  time.Duration
+	maxPing   int
+	samples   int
+	warning   string
+	reason    string
+	whitelist map[string]bool
+	periods   []period
+
+	strikes struct {
+		sync.Mutex
+		m map[string]int
+	}
+}
+
+//New creates a PingKick with given Config
+func New(c Config, client Client) *PingKick {
+	cfg := c.GetConfig()
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Second * 30
+	}
+	if cfg.Samples == 0 {
+		cfg.Samples = 3
+	}
+	if cfg.Reason == "" {
+		cfg.Reason = "High Ping"
+	}
+
+	p := &PingKick{
+		client:    client,
+		interval:  cfg.Interval,
+		maxPing:   cfg.MaxPing,
+		samples:   cfg.Samples,
+		warning:   cfg.Warning,
+		reason:    cfg.Reason,
+		whitelist: make(map[string]bool),
+	}
+	p.strikes.m = make(map[string]int)
+	for _, guid := range cfg.Whitelist {
+		p.whitelist[strings.ToLower(guid)] = true
+	}
+	for _, v := range cfg.Periods {
+		from, err := parseClock(v.From)
+		if err != nil {
+			glog.Errorf("Ignoring PingKick Period %v-%v: %v", v.From, v.To, err)
+			continue
+		}
+		to, err := parseClock(v.To)
+		if err != nil {
+			glog.Errorf("Ignoring PingKick Period %v-%v: %v", v.From, v.To, err)
+			continue
+		}
+		p.periods = append(p.periods, period{from: from, to: to, maxPing: v.MaxPing, samples: v.Samples})
+	}
+	return p
+}
+
+//Start polling the player list
+func (p *PingKick) Start() {
+	go p.loop()
+}
+
+func (p *PingKick) loop() {
+	for {
+		glog.V(10).Infoln("Looping in PingKick")
+		time.Sleep(p.interval)
+		list, err := players.List(p.client)
+		if err != nil {
+			glog.V(2).Infoln("PingKick could not retrieve Players:", err)
+			continue
+		}
+		p.check(list, time.Now())
+	}
+}
+
+//limits returns the ping limit and sample count active at the given time
+func (p *PingKick) limits(now time.Time) (maxPing, samples int) {
+	maxPing, samples = p.maxPing, p.samples
+	minute := now.Hour()*60 + now.Minute()
+	for _, v := range p.periods {
+		if !v.contains(minute) {
+			continue
+		}
+		if v.maxPing > 0 {
+			maxPing = v.maxPing
+		}
+		if v.samples > 0 {
+			samples = v.samples
+		}
+		break
+	}
+	return
+}
+
+func (p *PingKick) check(list []players.Player, now time.Time) {
+	maxPing, samples := p.limits(now)
+	if maxPing <= 0 {
+		return
+	}
+	p.strikes.Lock()
+	defer p.strikes.Unlock()
+	strikes := make(map[string]int)
+	for _, v := range list {
+		if v.GUID != "" && p.whitelist[v.GUID] {
+			continue
+		}
+		if v.Ping <= maxPing {
+			continue
+		}
+		key := playerKey(v)
+		count := p.strikes.m[key] + 1
+		vars := message.Vars{"name": v.Name, "ping": v.Ping, "max": maxPing, "samples": samples}
+		if count >= samples {
+			glog.Infof("Kicking %v for exceeding the ping limit (%vms > %vms)", v.Name, v.Ping, maxPing)
+			p.client.RunCommand(fmt.Sprintf("kick %d %s", v.Number, message.Render(p.reason, vars)), nil)
+			continue
+		}
+		if count == 1 && p.warning != "" {
+			glog.V(2).Infof("Warning %v for exceeding the ping limit (%vms > %vms)", v.Name, v.Ping, maxPing)
+			p.client.RunCommand(fmt.Sprintf("say %d %s", v.Number, message.Render(p.warning, vars)), nil)
+		}
+		strikes[key] = count
+	}
+	p.strikes.m = strikes
+}
+
+func playerKey(p players.Player) string {
+	if p.GUID != "" {
+		return p.GUID
+	}
+	return fmt.Sprintf("#%d %s", p.Number, p.Name)
+}
+
+func (v period) contains(minute int) bool {
+	if v.from <= v.to {
+		return minute >= v.from && minute < v.to
+	}
+	return minute >= v.from || minute < v.to
+}
+
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
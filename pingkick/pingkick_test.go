@@ -0,0 +1,91 @@
+package pingkick
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) Query(cmd string, timeout time.Duration) (string, error) {
+	return "", nil
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+func Test_check(t *testing.T) {
+	client := &fakeClient{}
+	p := New(Cfg{
+		MaxPing:   200,
+		Samples:   3,
+		Warning:   "Ping {ping} > {max}",
+		Reason:    "High Ping",
+		Whitelist: []string{"0123456789ABCDEF0123456789ABCDEF"},
+	}, client)
+	list := []players.Player{
+		{Number: 1, Name: "Steve", Ping: 300, GUID: "fedcba9876543210fedcba9876543210"},
+		{Number: 2, Name: "Admin", Ping: 500, GUID: "0123456789abcdef0123456789abcdef"},
+		{Number: 3, Name: "Good", Ping: 50},
+	}
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.Local)
+	for i := 0; i < 3; i++ {
+		p.check(list, now)
+	}
+	expected := []string{"say 1 Ping 300 > 200", "kick 1 High Ping"}
+	if len(client.commands) != len(expected) {
+		t.Fatal("Expected:", expected, "Got:", client.commands)
+	}
+	for i, v := range expected {
+		if client.commands[i] != v {
+			t.Error("Expected:", v, "Got:", client.commands[i])
+		}
+	}
+}
+
+func Test_checkResetsStrikes(t *testing.T) {
+	client := &fakeClient{}
+	p := New(Cfg{MaxPing: 200, Samples: 2}, client)
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.Local)
+	high := []players.Player{{Number: 1, Name: "Steve", Ping: 300}}
+	low := []players.Player{{Number: 1, Name: "Steve", Ping: 100}}
+	p.check(high, now)
+	p.check(low, now)
+	p.check(high, now)
+	if len(client.commands) != 0 {
+		t.Error("Expected no Commands Got:", client.commands)
+	}
+}
+
+func Test_limits(t *testing.T) {
+	p := New(Cfg{
+		MaxPing: 200,
+		Samples: 3,
+		Periods: []Period{
+			{From: "22:00", To: "06:00", MaxPing: 350},
+			{From: "12:00", To: "13:00", Samples: 5},
+		},
+	}, &fakeClient{})
+	var tests = []struct {
+		hour, minute     int
+		maxPing, samples int
+	}{
+		{23, 30, 350, 3},
+		{5, 59, 350, 3},
+		{6, 0, 200, 3},
+		{12, 30, 200, 5},
+		{18, 0, 200, 3},
+	}
+	for _, v := range tests {
+		maxPing, samples := p.limits(time.Date(2017, 1, 1, v.hour, v.minute, 0, 0, time.Local))
+		if maxPing != v.maxPing || samples != v.samples {
+			t.Errorf("%02d:%02d Expected: %v/%v Got: %v/%v", v.hour, v.minute, v.maxPing, v.samples, maxPing, samples)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package playerdb
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Record holds everything known about a player
+type Record struct {
+	GUID        string    `json:"guid"`
+	SteamID     string    `json:"steamId,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Visits      int       `json:"visits,omitempty"`
+	FirstSeen   time.Time `json:"firstSeen,omitempty"`
+	LastSeen    time.Time `json:"lastSeen,omitempty"`
+	Offences    int       `json:"offences,omitempty"`
+	LastOffence time.Time `json:"lastOffence,omitempty"`
+	Names       []string  `json:"names,omitempty"`
+	IPs         []string  `json:"ips,omitempty"`
+	Warnings    []Remark  `json:"warnings,omitempty"`
+	Notes       []Remark  `json:"notes,omitempty"`
+	RelayOptOut bool      `json:"relayOptOut,omitempty"`
+	//PreviousName is set if the name changed on the latest visit
+	PreviousName string `json:"previousName,omitempty"`
+	//Sanctioned is the amount of warnings the latest sanction was applied for
+	Sanctioned int `json:"sanctioned,omitempty"`
+}
+
+//Remark is a warning or note attached to a player by an admin
+type Remark struct {
+	Time   time.Time `json:"time"`
+	Author string    `json:"author,omitempty"`
+	Text   string    `json:"text"`
+}
+
+//DB is a json file backed store of player Records
+type DB struct {
+	path string
+
+	records struct {
+		sync.Mutex
+		m map[string]*Record
+	}
+}
+
+//Open the DB at path, a missing file results in an empty DB
+func Open(path string) (*DB, error) {
+	db := &DB{path: path}
+	db.records.m = make(map[string]*Record)
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []*Record
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, err
+	}
+	for _, r := range list {
+		db.records.m[r.GUID] = r
+	}
+	return db, nil
+}
+
+//Get the Record for guid, unknown GUIDs return an empty Record
+func (db *DB) Get(guid string) Record {
+	guid = strings.ToLower(guid)
+	db.records.Lock()
+	defer db.records.Unlock()
+	if r, ok := db.records.m[guid]; ok {
+		return *r
+	}
+	return Record{GUID: guid}
+}
+
+//Update the Record for guid with fn and persist the DB
+func (db *DB) Update(guid string, fn func(r *Record)) (Record, error) {
+	guid = strings.ToLower(guid)
+	db.records.Lock()
+	defer db.records.Unlock()
+	r, ok := db.records.m[guid]
+	if !ok {
+		r = &Record{GUID: guid}
+		db.records.m[guid] = r
+	}
+	fn(r)
+	return *r, db.save()
+}
+
+//MaxHistory is the amount of names and IPs kept per player
+var MaxHistory = 20
+
+//Visit records a player joining the server with his name and IP
+func (db *DB) Visit(guid, name, ip string) (Record, error) {
+	return db.Update(guid, func(r *Record) {
+		now := time.Now()
+		if r.Visits == 0 {
+			r.FirstSeen = now
+		}
+		r.Visits++
+		r.LastSeen = now
+		r.PreviousName = ""
+		if r.Name != "" && r.Name != name {
+			r.PreviousName = r.Name
+		}
+		r.Name = name
+		r.Names = appendHistory(r.Names, name)
+		if ip != "" {
+			r.IPs = appendHistory(r.IPs, ip)
+		}
+	})
+}
+
+//Find returns all Records matching fn
+func (db *DB) Find(fn func(r Record) bool) []Record {
+	db.records.Lock()
+	defer db.records.Unlock()
+	var result []Record
+	for _, r := range db.records.m {
+		if fn(*r) {
+			result = append(result, *r)
+		}
+	}
+	return result
+}
+
+//appendHistory moves value to the end of list and drops the oldest entries
+func appendHistory(list []string, value string) []string {
+	result := make([]string, 0, len(list)+1)
+	for _, v := range list {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	result = append(result, value)
+	if len(result) > MaxHistory {
+		result = result[len(result)-MaxHistory:]
+	}
+	return result
+}
+
+//Warn attaches a warning to the Record of guid
+func (db *DB) Warn(guid, author, reason string) (Record, error) {
+	return db.Update(guid, func(r *Record) {
+		r.Warnings = append(r.Warnings, Remark{Time: time.Now(), Author: author, Text: reason})
+	})
+}
+
+//Note attaches a free-text note to the Record of guid
+func (db *DB) Note(guid, author, text string) (Record, error) {
+	return db.Update(guid, func(r *Record) {
+		r.Notes = append(r.Notes, Remark{Time: time.Now(), Author: author, Text: text})
+	})
+}
+
+//LinkSteamID stores the SteamID64 a GUID was computed from
+func (db *DB) LinkSteamID(guid, steamID string) error {
+	if db.Get(guid).SteamID == steamID {
+		return nil
+	}
+	_, err := db.Update(guid, func(r *Record) {
+		r.SteamID = steamID
+	})
+	return err
+}
+
+func (db *DB) save() error {
+	list := make([]*Record, 0, len(db.records.m))
+	for _, r := range db.records.m {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].GUID < list[j].GUID })
+	content, err := json.MarshalIndent(list, "", "    ")
+	if err != nil {
+		return err
+	}
+	tmp := db.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, db.path)
+}
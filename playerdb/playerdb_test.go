@@ -0,0 +1,62 @@
+package playerdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func Test_UpdatePersists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "playerdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "players.json")
+
+	db, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Update("0123456789ABCDEF0123456789ABCDEF", func(r *Record) { r.Offences++ }); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := db.Get("0123456789abcdef0123456789abcdef"); r.Offences != 1 {
+		t.Error("Expected: 1 Offence Got:", r.Offences)
+	}
+}
+
+func Test_Visit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "playerdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := Open(path.Join(dir, "players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	guid := "0123456789abcdef0123456789abcdef"
+	db.Visit(guid, "Steve", "10.0.0.1")
+	r, err := db.Visit(guid, "Steve2", "10.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Visits != 2 || r.Name != "Steve2" || r.PreviousName != "Steve" || r.FirstSeen.IsZero() || r.LastSeen.Before(r.FirstSeen) {
+		t.Error("Unexpected Record:", r)
+	}
+	r, _ = db.Visit(guid, "Steve2", "10.0.0.1")
+	if r.PreviousName != "" || !reflect.DeepEqual(r.Names, []string{"Steve", "Steve2"}) || !reflect.DeepEqual(r.IPs, []string{"10.0.0.2", "10.0.0.1"}) {
+		t.Error("Unexpected History:", r)
+	}
+	if found := db.Find(func(r Record) bool { return r.Visits == 3 }); len(found) != 1 {
+		t.Error("Expected to find 1 Record Got:", found)
+	}
+}
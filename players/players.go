@@ -0,0 +1,73 @@
+package players
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//QueryTimeout is the time to wait for the players command to respond
+var QueryTimeout = time.Second * 5
+
+//Player as listed by the players command
+type Player struct {
+	Number   int
+	IP       string
+	Port     string
+	Ping     int
+	GUID     string
+	Verified bool
+	Name     string
+	Lobby    bool
+	Joined   time.Time
+	Country  string
+}
+
+//Querier runs a RCon command and returns its response
+type Querier interface {
+	Query(cmd string, timeout time.Duration) (string, error)
+}
+
+// 0   127.0.0.1:2304   31   0123456789abcdef0123456789abcdef(OK) Name (Lobby)
+var playerLine = regexp.MustCompile(`^(\d+)\s+([0-9.]+):(\d+)\s+(-?\d+)\s+(\S+)\s+(.+)$`)
+var guidField = regexp.MustCompile(`^([0-9a-fA-F]{32})\((OK|\?)\)$`)
+
+//List queries the server for all connected players
+func List(q Querier) ([]Player, error) {
+	res, err := q.Query("players", QueryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(res), nil
+}
+
+//Parse the output of the players command
+func Parse(list string) []Player {
+	var result []Player
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		match := playerLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		p := Player{
+			IP:   match[2],
+			Port: match[3],
+			Name: match[6],
+		}
+		p.Number, _ = strconv.Atoi(match[1])
+		p.Ping, _ = strconv.Atoi(match[4])
+		if guid := guidField.FindStringSubmatch(match[5]); guid != nil {
+			p.GUID = strings.ToLower(guid[1])
+			p.Verified = guid[2] == "OK"
+		}
+		if strings.HasSuffix(p.Name, " (Lobby)") {
+			p.Name = strings.TrimSuffix(p.Name, " (Lobby)")
+			p.Lobby = true
+		}
+		result = append(result, p)
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package players
+
+import (
+	"testing"
+	"time"
+)
+
+const listOutput = `Players on server:
+[#] [IP Address]:[Port] [Ping] [GUID] [Name]
+--------------------------------------------------
+0   127.0.0.1:2304        31   0123456789abcdef0123456789abcdef(OK) Steve
+1   10.0.0.12:2316        152  fedcba9876543210fedcba9876543210(?) Kevin Mc Name (Lobby)
+2   10.0.0.13:2304        -1   - Joiner
+(3 players in total)
+`
+
+func Test_Parse(t *testing.T) {
+	list := Parse(listOutput)
+	if len(list) != 3 {
+		t.Fatal("Expected: 3 Players Got:", len(list))
+	}
+	expected := []Player{
+		{Number: 0, IP: "127.0.0.1", Port: "2304", Ping: 31, GUID: "0123456789abcdef0123456789abcdef", Verified: true, Name: "Steve"},
+		{Number: 1, IP: "10.0.0.12", Port: "2316", Ping: 152, GUID: "fedcba9876543210fedcba9876543210", Name: "Kevin Mc Name", Lobby: true},
+		{Number: 2, IP: "10.0.0.13", Port: "2304", Ping: -1, Name: "Joiner"},
+	}
+	for i, v := range expected {
+		if list[i] != v {
+			t.Error("Expected:", v, "Got:", list[i])
+		}
+	}
+}
+
+type fakeQuerier string
+
+func (f fakeQuerier) Query(cmd string, timeout time.Duration) (string, error) {
+	return string(f), nil
+}
+
+func Test_List(t *testing.T) {
+	list, err := List(fakeQuerier(listOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Error("Expected: 3 Players Got:", len(list))
+	}
+}
+
+func Test_RosterSync(t *testing.T) {
+	r := NewRoster()
+	joined := time.Now().Add(-time.Hour)
+	r.Add(Player{Number: 0, Name: "Steve", Joined: joined})
+	r.Add(Player{Number: 1, Name: "Gone"})
+	r.Sync(Parse(listOutput))
+	if r.Count() != 3 {
+		t.Fatal("Expected: 3 Players Got:", r.Count())
+	}
+	p, ok := r.ByNumber(0)
+	if !ok || !p.Joined.Equal(joined) || p.GUID != "0123456789abcdef0123456789abcdef" {
+		t.Error("Expected Steve to keep his join time Got:", p)
+	}
+	if p, ok := r.ByName("kevin mc name"); !ok || p.Number != 1 || p.Joined.IsZero() {
+		t.Error("Expected Kevin Mc Name Got:", p)
+	}
+}
+
+func Test_RosterSender(t *testing.T) {
+	r := NewRoster()
+	r.Add(Player{Number: 0, Name: "Admin"})
+	r.Add(Player{Number: 1, Name: "Steve"})
+	r.Add(Player{Number: 2, Name: "Steve Jr"})
+	var tests = []struct {
+		message string
+		number  int
+		text    string
+		err     error
+	}{
+		{"Admin: hello: there", 0, "hello: there", nil},
+		{"Steve Jr: hi", 2, "hi", nil},
+		{"admin: !kick Steve", 0, "", ErrUnknownSender},
+		{"Nobody: hi", 0, "", ErrUnknownSender},
+	}
+	for _, v := range tests {
+		p, text, err := r.Sender(v.message)
+		if err != v.err || (err == nil && (p.Number != v.number || text != v.text)) {
+			t.Errorf("%q Expected: #%d %q %v Got: #%d %q %v", v.message, v.number, v.text, v.err, p.Number, text, err)
+		}
+	}
+
+	r.Add(Player{Number: 3, Name: "Admin: !kick Steve"})
+	if _, _, err := r.Sender("Admin: !kick Steve: x"); err != ErrAmbiguousSender {
+		t.Error("Expected ErrAmbiguousSender for Name containing the separator Got:", err)
+	}
+	if p, _, err := r.Sender("Admin: hi"); err != nil || p.Number != 0 {
+		t.Error("Expected Admin Got:", p, err)
+	}
+	r.Add(Player{Number: 4, Name: "Steve"})
+	if _, _, err := r.Sender("Steve: hi"); err != ErrAmbiguousSender {
+		t.Error("Expected ErrAmbiguousSender for duplicate Name Got:", err)
+	}
+}
@@ -0,0 +1,161 @@
+package players
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+var (
+	//ErrUnknownSender is returned if no player on the server sent a chat message
+	ErrUnknownSender = errors.New("Sender is not on the server")
+	//ErrAmbiguousSender is returned if the sender of a chat message can not be told apart from another player
+	ErrAmbiguousSender = errors.New("Sender is ambiguous")
+)
+
+//Roster keeps track of the players currently on the server
+type Roster struct {
+	sync.RWMutex
+	players map[int]Player
+}
+
+//NewRoster creates an empty Roster
+func NewRoster() *Roster {
+	return &Roster{
+		players: make(map[int]Player),
+	}
+}
+
+//Add a connected player
+func (r *Roster) Add(p Player) {
+	if p.Joined.IsZero() {
+		p.Joined = time.Now()
+	}
+	r.Lock()
+	r.players[p.Number] = p
+	r.Unlock()
+}
+
+//SetGUID of the player with the given number
+func (r *Roster) SetGUID(number int, guid string) {
+	r.Lock()
+	if p, ok := r.players[number]; ok {
+		p.GUID = strings.ToLower(guid)
+		p.Verified = true
+		r.players[number] = p
+	}
+	r.Unlock()
+}
+
+//SetCountry of the player with the given number
+func (r *Roster) SetCountry(number int, country string) {
+	r.Lock()
+	if p, ok := r.players[number]; ok {
+		p.Country = country
+		r.players[number] = p
+	}
+	r.Unlock()
+}
+
+//Remove a disconnected player
+func (r *Roster) Remove(number int) {
+	r.Lock()
+	delete(r.players, number)
+	r.Unlock()
+}
+
+//ByNumber returns the player with the given number
+func (r *Roster) ByNumber(number int) (Player, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	p, ok := r.players[number]
+	return p, ok
+}
+
+//ByName returns the player with the given name (case insensitive)
+func (r *Roster) ByName(name string) (Player, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	for _, p := range r.players {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Player{}, false
+}
+
+//Sender returns the player who sent the chat message "name: text" and the text
+//Names are matched exactly with the longest match first. Messages are refused if the name is shared by
+//several players or contains ": ", as such a sender could pose as another player
+func (r *Roster) Sender(message string) (Player, string, error) {
+	r.RLock()
+	defer r.RUnlock()
+	var found []Player
+	for _, p := range r.players {
+		if p.Name != "" && strings.HasPrefix(message, p.Name+": ") {
+			found = append(found, p)
+		}
+	}
+	if len(found) == 0 {
+		return Player{}, "", ErrUnknownSender
+	}
+	sort.Slice(found, func(i, j int) bool { return len(found[i].Name) > len(found[j].Name) })
+	p := found[0]
+	if strings.Contains(p.Name, ": ") || (len(found) > 1 && found[1].Name == p.Name) {
+		return Player{}, "", ErrAmbiguousSender
+	}
+	return p, strings.TrimPrefix(message, p.Name+": "), nil
+}
+
+//All returns a copy of all players on the server
+func (r *Roster) All() []Player {
+	r.RLock()
+	defer r.RUnlock()
+	list := make([]Player, 0, len(r.players))
+	for _, p := range r.players {
+		list = append(list, p)
+	}
+	return list
+}
+
+//Count of players on the server
+func (r *Roster) Count() int {
+	r.RLock()
+	defer r.RUnlock()
+	return len(r.players)
+}
+
+//Sync replaces the roster with the given player list, keeping join time and country of known players
+func (r *Roster) Sync(list []Player) {
+	now := time.Now()
+	r.Lock()
+	players := make(map[int]Player, len(list))
+	for _, p := range list {
+		p.Joined = now
+		if old, ok := r.players[p.Number]; ok && old.Name == p.Name {
+			p.Joined = old.Joined
+			p.Country = old.Country
+		}
+		players[p.Number] = p
+	}
+	r.players = players
+	r.Unlock()
+}
+
+//Poll syncs the roster with the servers player list in the given interval
+func (r *Roster) Poll(q Querier, interval time.Duration) {
+	for {
+		glog.V(10).Infoln("Looping in Roster Poll")
+		list, err := List(q)
+		if err != nil {
+			glog.V(2).Infoln("Roster could not retrieve Players:", err)
+		} else {
+			r.Sync(list)
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,324 @@
+package procwatch
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+//Action types of scheduled entries
+const (
+	ActionCommand = "command"
+	ActionRestart = "restart"
+	ActionExec    = "exec"
+	ActionHTTP    = "http"
+	ActionBatch   = "batch"
+	ActionStop    = "stop"
+	ActionStart   = "start"
+)
+
+//Default timeouts of exec and http actions
+const (
+	DefaultExecTimeout = time.Minute * 5
+	DefaultHTTPTimeout = time.Second * 30
+)
+
+//execWaitDelay is how long output is read after an exec action exited
+const execWaitDelay = time.Second
+
+//maxOutput is the amount of captured output included in errors
+const maxOutput = 512
+
+//Task is what an entry does when it fires, an entry without action sends its command or restarts
+type Task struct {
+	Action string `json:"action,omitempty"`
+	//Exec is the program with its arguments run by exec actions
+	Exec []string `json:"exec,omitempty"`
+	//URL, Method (default POST) and Body of the request sent by http actions
+	URL    string `json:"url,omitempty"`
+	Method string `json:"method,omitempty"`
+	Body   string `json:"body,omitempty"`
+	//Batch are the commands sent by batch actions
+	Batch []BatchStep `json:"batch,omitempty"`
+	//Timeout of exec and http actions like "10m"
+	Timeout string `json:"timeout,omitempty"`
+}
+
+//BatchStep is a RCon command sent after Delay
+type BatchStep struct {
+	Command string `json:"command"`
+	Delay   string `json:"delay,omitempty"`
+}
+
+//kind returns the action type of the entry
+func (e SchedulerEntity) kind() string {
+	switch {
+	case e.Action != "":
+		return e.Action
+	case e.Restart:
+		return ActionRestart
+	default:
+		return ActionCommand
+	}
+}
+
+//timeout returns the timeout of the entry or def if it has none
+func (t Task) timeout(def time.Duration) (time.Duration, error) {
+	if t.Timeout == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(t.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout: %v", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout %v is not positive", d)
+	}
+	return d, nil
+}
+
+//delays returns the parsed delays of the batch steps
+func (t Task) delays() ([]time.Duration, error) {
+	delays := make([]time.Duration, len(t.Batch))
+	for i, step := range t.Batch {
+		if step.Command == "" {
+			return nil, fmt.Errorf("batch step %d has no command", i+1)
+		}
+		if step.Delay == "" {
+			continue
+		}
+		d, err := time.ParseDuration(step.Delay)
+		if err != nil {
+			return nil, fmt.Errorf("batch step %d: invalid delay: %v", i+1, err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("batch step %d: delay %v is negative", i+1, d)
+		}
+		delays[i] = d
+	}
+	return delays, nil
+}
+
+//checkAction returns an error if the action of the entry lacks required fields or the watcher it requires
+func (e SchedulerEntity) checkAction(useWatcher bool) error {
+	switch e.kind() {
+	case ActionCommand:
+		if e.Command == "" {
+			return errors.New("command action requires a command")
+		}
+	case ActionRestart:
+	case ActionStop, ActionStart:
+		if !useWatcher {
+			return fmt.Errorf("%s action requires the watcher", e.kind())
+		}
+	case ActionExec:
+		if len(e.Exec) == 0 || e.Exec[0] == "" {
+			return errors.New("exec action requires a program")
+		}
+		_, err := e.timeout(DefaultExecTimeout)
+		return err
+	case ActionHTTP:
+		if e.URL == "" {
+			return errors.New("http action requires an url")
+		}
+		if _, err := http.NewRequest(e.method(), e.URL, nil); err != nil {
+			return err
+		}
+		_, err := e.timeout(DefaultHTTPTimeout)
+		return err
+	case ActionBatch:
+		if len(e.Batch) == 0 {
+			return errors.New("batch action requires at least one step")
+		}
+		_, err := e.delays()
+		return err
+	default:
+		return fmt.Errorf("unknown action %q", e.Action)
+	}
+	return nil
+}
+
+func (t Task) method() string {
+	if t.Method == "" {
+		return "POST"
+	}
+	return strings.ToUpper(t.Method)
+}
+
+//action describes what the entry does when it fires
+func (e SchedulerEntity) action() string {
+	switch e.kind() {
+	case ActionRestart:
+		if len(e.Warnings) == 0 {
+			return "restart"
+		}
+		warnings := make([]string, len(e.Warnings))
+		for i, minutes := range e.Warnings {
+			warnings[i] = fmt.Sprint(minutes)
+		}
+		return fmt.Sprintf("restart with warnings %s minutes before", strings.Join(warnings, ", "))
+	case ActionExec:
+		timeout, _ := e.timeout(DefaultExecTimeout)
+		return fmt.Sprintf("run %q with timeout %v", strings.Join(e.Exec, " "), timeout)
+	case ActionHTTP:
+		return fmt.Sprintf("%s %s", e.method(), e.URL)
+	case ActionBatch:
+		steps := make([]string, len(e.Batch))
+		for i, step := range e.Batch {
+			steps[i] = fmt.Sprintf("%q", step.Command)
+			if step.Delay != "" {
+				steps[i] = fmt.Sprintf("after %s %s", step.Delay, steps[i])
+			}
+		}
+		return "send " + strings.Join(steps, ", ")
+	case ActionStop:
+		return "stop the server"
+	case ActionStart:
+		return "start the server"
+	default:
+		return fmt.Sprintf("send %q", e.Command)
+	}
+}
+
+//newAction returns the action run when the validated entry fires
+func (w *Watcher) newAction(entry SchedulerEntity) func() error {
+	switch entry.kind() {
+	case ActionRestart:
+		return w.Restart
+	case ActionExec:
+		timeout, _ := entry.timeout(DefaultExecTimeout)
+		return func() error {
+			return runExec(entry.Exec, timeout)
+		}
+	case ActionHTTP:
+		timeout, _ := entry.timeout(DefaultHTTPTimeout)
+		return func() error {
+			return callHTTP(entry.method(), entry.URL, entry.Body, timeout)
+		}
+	case ActionBatch:
+		delays, _ := entry.delays()
+		return func() error {
+			return w.runBatch(entry.Batch, delays)
+		}
+	case ActionStop:
+		return w.StopServer
+	case ActionStart:
+		return w.StartServer
+	default:
+		command := entry.Command
+		return func() error {
+			glog.V(2).Infoln("Sending Command to Channel: ", command)
+			if !w.send(command) {
+				return fmt.Errorf("%q was not sent", command)
+			}
+			return nil
+		}
+	}
+}
+
+//runExec runs the program and logs its output, it is killed after timeout
+func runExec(args []string, timeout time.Duration) error {
+	glog.V(2).Infof("Executing %v", args)
+	cmd := exec.Command(args[0], args[1:]...)
+	setProcessGroup(cmd)
+	//output goes through a pipe so children left behind by the program can not block the action
+	r, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	err = cmd.Start()
+	pw.Close()
+	if err != nil {
+		return err
+	}
+	read := make(chan []byte, 1)
+	go func() {
+		output, _ := ioutil.ReadAll(r)
+		read <- output
+	}()
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	timedOut := false
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		timedOut = true
+		killProcessGroup(cmd)
+		err = <-done
+	}
+	var output []byte
+	select {
+	case output = <-read:
+	case <-time.After(execWaitDelay):
+		r.Close()
+		output = <-read
+	}
+	if len(output) > 0 {
+		glog.Infof("Output of %v:\n%s", args, output)
+	}
+	if timedOut {
+		return fmt.Errorf("timed out after %v", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, truncate(output))
+	}
+	return nil
+}
+
+//callHTTP sends a request to url and fails on non 2xx responses
+func callHTTP(method, url, body string, timeout time.Duration) error {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := &http.Client{Timeout: timeout}
+	glog.V(2).Infof("Calling %v %v", method, url)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	content, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%v: %s", resp.Status, truncate(content))
+	}
+	glog.V(2).Infof("%v %v returned %v", method, url, resp.Status)
+	return nil
+}
+
+//runBatch sends the commands of steps after their delays, it stops at the first command not received
+func (w *Watcher) runBatch(steps []BatchStep, delays []time.Duration) error {
+	for i, step := range steps {
+		if delays[i] > 0 {
+			time.Sleep(delays[i])
+		}
+		glog.V(2).Infoln("Sending Command to Channel: ", step.Command)
+		if !w.send(step.Command) {
+			return fmt.Errorf("step %d %q was not sent", i+1, step.Command)
+		}
+	}
+	return nil
+}
+
+func truncate(output []byte) string {
+	s := strings.TrimSpace(string(output))
+	if len(s) > maxOutput {
+		s = s[:maxOutput] + "..."
+	}
+	return s
+}
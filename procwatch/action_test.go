@@ -0,0 +1,105 @@
+package procwatch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_CheckAction(t *testing.T) {
+	var tests = []struct {
+		entry SchedulerEntity
+		valid bool
+	}{
+		{SchedulerEntity{Command: "say -1 Hello"}, true},
+		{SchedulerEntity{}, false},
+		{SchedulerEntity{Restart: true}, true},
+		{SchedulerEntity{Task: Task{Action: "exec", Exec: []string{"backup.sh"}, Timeout: "10m"}}, true},
+		{SchedulerEntity{Task: Task{Action: "exec"}}, false},
+		{SchedulerEntity{Task: Task{Action: "exec", Exec: []string{"backup.sh"}, Timeout: "soon"}}, false},
+		{SchedulerEntity{Task: Task{Action: "http", URL: "http://127.0.0.1/hook"}}, true},
+		{SchedulerEntity{Task: Task{Action: "http"}}, false},
+		{SchedulerEntity{Task: Task{Action: "batch", Batch: []BatchStep{{Command: "#lock"}, {Command: "#shutdown", Delay: "30s"}}}}, true},
+		{SchedulerEntity{Task: Task{Action: "batch", Batch: []BatchStep{{Command: "#lock", Delay: "-1s"}}}}, false},
+		{SchedulerEntity{Task: Task{Action: "batch"}}, false},
+		{SchedulerEntity{Task: Task{Action: "stop"}}, true},
+		{SchedulerEntity{Task: Task{Action: "reboot"}}, false},
+	}
+	for _, v := range tests {
+		if err := v.entry.checkAction(true); (err == nil) != v.valid {
+			t.Errorf("%v Expected valid: %v Got: %v", v.entry.action(), v.valid, err)
+		}
+	}
+	if err := (SchedulerEntity{Task: Task{Action: "stop"}}).checkAction(false); err == nil {
+		t.Error("Expected stop Action to require the Watcher")
+	}
+}
+
+func Test_Exec(t *testing.T) {
+	if err := runExec([]string{"sh", "-c", "echo backup done"}, time.Second); err != nil {
+		t.Error(err)
+	}
+	err := runExec([]string{"sh", "-c", "echo disk full; exit 1"}, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Error("Expected Error with Output Got:", err)
+	}
+	if err := runExec([]string{"sleep", "5"}, time.Millisecond*50); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Error("Expected Timeout Got:", err)
+	}
+	start := time.Now()
+	if err := runExec([]string{"sh", "-c", "sleep 3; echo"}, time.Millisecond*100); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Error("Expected Timeout Got:", err)
+	}
+	if time.Since(start) > time.Second*2 {
+		t.Error("Expected forked children to be killed on Timeout, returned after", time.Since(start))
+	}
+	start = time.Now()
+	if err := runExec([]string{"sh", "-c", "sleep 3 & echo started"}, time.Second*5); err != nil {
+		t.Error(err)
+	}
+	if time.Since(start) > time.Second*2 {
+		t.Error("Expected background children not to block, returned after", time.Since(start))
+	}
+}
+
+func Test_HTTP(t *testing.T) {
+	var method, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		buf := make([]byte, 64)
+		n, _ := r.Body.Read(buf)
+		body = string(buf[:n])
+		if r.URL.Path == "/fail" {
+			http.Error(w, "broken", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	if err := callHTTP("POST", server.URL+"/hook", `{"event":"backup"}`, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if method != "POST" || body != `{"event":"backup"}` {
+		t.Error("Unexpected Request:", method, body)
+	}
+	if err := callHTTP("GET", server.URL+"/fail", "", time.Second); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Error("Expected Error with Response Got:", err)
+	}
+}
+
+func Test_Batch(t *testing.T) {
+	w := New(Cfg{UseScheduler: true})
+	entry := SchedulerEntity{Every: "1h", Task: Task{Action: "batch", Batch: []BatchStep{{Command: "#lock"}, {Command: "#unlock", Delay: "10ms"}}}}
+	action := w.newAction(entry)
+	done := make(chan error)
+	go func() { done <- action() }()
+	for _, expected := range []string{"#lock", "#unlock"} {
+		if cmd := <-w.cmdChan; cmd != expected {
+			t.Error("Expected:", expected, "Got:", cmd)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,222 @@
+package procwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+//Catch-up policies for occurrences missed while gorcon-arma was not running
+const (
+	//CatchUpSkip drops missed occurrences, this is the default
+	CatchUpSkip = "skip"
+	//CatchUpOnStart runs the entry once on start if at least one occurrence was missed
+	CatchUpOnStart = "run-once-on-start"
+	//CatchUpWithin runs the entry once on start if an occurrence was missed within the given duration
+	CatchUpWithin = "run-if-within"
+)
+
+//catchUp is the parsed catch-up policy of an entry
+type catchUp struct {
+	policy string
+	within time.Duration
+}
+
+//parseCatchUp parses policies like "run-if-within 2h"
+func parseCatchUp(s string) (catchUp, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return catchUp{policy: CatchUpSkip}, nil
+	}
+	c := catchUp{policy: fields[0]}
+	switch {
+	case (c.policy == CatchUpSkip || c.policy == CatchUpOnStart) && len(fields) == 1:
+		return c, nil
+	case c.policy == CatchUpWithin && len(fields) == 2:
+		d, err := time.ParseDuration(fields[1])
+		if err != nil {
+			return c, fmt.Errorf("invalid catchUp duration: %v", err)
+		}
+		if d <= 0 {
+			return c, fmt.Errorf("catchUp duration %v is not positive", d)
+		}
+		c.within = d
+		return c, nil
+	}
+	return c, fmt.Errorf("invalid catchUp %q, expected skip, run-once-on-start or run-if-within <duration>", s)
+}
+
+//missed returns the first occurrence of jb after last which was missed by now and is caught up by its policy,
+//zero time if there is none
+func (jb *job) missed(last, now time.Time) time.Time {
+	var from time.Time
+	switch jb.catchUp.policy {
+	case CatchUpOnStart:
+		from = last
+	case CatchUpWithin:
+		from = now.Add(-jb.catchUp.within)
+		if last.After(from) {
+			from = last
+		}
+	default:
+		return time.Time{}
+	}
+	t := jb.schedule.Next(from)
+	if t.IsZero() || t.After(now) {
+		return time.Time{}
+	}
+	return t
+}
+
+//stateKey identifies the persisted state of jb by its name or, for unnamed entries, by all of its fields
+//so inserting or reordering entries does not move the state of one entry to another
+func (jb *job) stateKey() string {
+	if jb.entry.Name != "" {
+		return jb.entry.Name
+	}
+	return jb.entry.key()
+}
+
+//jobState is the persisted state of a job
+type jobState struct {
+	//Since is when the job was scheduled the first time
+	Since time.Time `json:"since"`
+	//Last is when the job fired the last time, including skipped occurrences
+	Last time.Time `json:"last,omitempty"`
+}
+
+//last returns the time after which occurrences of the job count as missed
+func (s jobState) last() time.Time {
+	if s.Last.After(s.Since) {
+		return s.Last
+	}
+	return s.Since
+}
+
+//stateStore persists the jobState of all jobs by their stateKey in a json file
+type stateStore struct {
+	path string
+
+	sync.Mutex
+	jobs map[string]jobState
+}
+
+//loadState reads the state at path, a missing file or empty path results in an empty state
+func loadState(path string) (*stateStore, error) {
+	s := &stateStore{path: path, jobs: make(map[string]jobState)}
+	if path == "" {
+		return s, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(content, &s.jobs); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+//get the state of the job with given key
+func (s *stateStore) get(key string) jobState {
+	s.Lock()
+	defer s.Unlock()
+	return s.jobs[key]
+}
+
+//track adds the jobs seen for the first time and drops the ones no longer scheduled
+func (s *stateStore) track(j *jobs) {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	keys := make(map[string]bool)
+	for _, jb := range j.list {
+		keys[jb.stateKey()] = true
+	}
+	for key := range s.jobs {
+		if !keys[key] {
+			delete(s.jobs, key)
+		}
+	}
+	for key := range keys {
+		if _, ok := s.jobs[key]; !ok {
+			s.jobs[key] = jobState{Since: now}
+		}
+	}
+	s.save()
+}
+
+//fired records an occurrence of the job with given key
+func (s *stateStore) fired(key string, t time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	state := s.jobs[key]
+	state.Last = t
+	s.jobs[key] = state
+	s.save()
+}
+
+func (s *stateStore) save() {
+	if s.path == "" {
+		return
+	}
+	content, err := json.MarshalIndent(s.jobs, "", "    ")
+	if err != nil {
+		glog.Errorln("Could not encode Schedule State:", err)
+		return
+	}
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		glog.Errorln("Could not save Schedule State:", err)
+		return
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		glog.Errorln("Could not save Schedule State:", err)
+	}
+}
+
+//catchUp fires the jobs which missed occurrences while gorcon-arma was not running according to their policy
+//They fire after delay so RCon can connect first, conditions, pauses and skips apply as usual.
+//Missed restarts are dropped if the watcher just started the server.
+func (w *Watcher) catchUp(delay time.Duration) {
+	now := time.Now()
+	type missedJob struct {
+		jb *job
+		at time.Time
+	}
+	var missed []missedJob
+	w.jobsLock.RLock()
+	if w.jobs != nil {
+		for _, jb := range w.jobs.list {
+			if at := jb.missed(w.state.get(jb.stateKey()).last(), now); !at.IsZero() {
+				missed = append(missed, missedJob{jb: jb, at: at})
+			}
+		}
+	}
+	w.jobsLock.RUnlock()
+	if len(missed) == 0 {
+		return
+	}
+	sort.Slice(missed, func(i, j int) bool { return missed[i].at.Before(missed[j].at) })
+
+	time.Sleep(delay)
+	for _, m := range missed {
+		if m.jb.entry.kind() == ActionRestart && w.useWatcher {
+			glog.Infof("Catching up %v missed at %v: server was started, dropping restart", m.jb.entry, m.at)
+			w.state.fired(m.jb.stateKey(), time.Now())
+			continue
+		}
+		glog.Infof("Catching up %v missed at %v", m.jb.entry, m.at)
+		w.fire(m.jb)
+	}
+}
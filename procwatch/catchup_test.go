@@ -0,0 +1,129 @@
+package procwatch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+func Test_ParseCatchUp(t *testing.T) {
+	var tests = []struct {
+		in     string
+		policy string
+		within time.Duration
+		valid  bool
+	}{
+		{"", CatchUpSkip, 0, true},
+		{"skip", CatchUpSkip, 0, true},
+		{"run-once-on-start", CatchUpOnStart, 0, true},
+		{"run-if-within 2h", CatchUpWithin, time.Hour * 2, true},
+		{"run-if-within", CatchUpWithin, 0, false},
+		{"run-if-within -1h", CatchUpWithin, 0, false},
+		{"always", "always", 0, false},
+	}
+	for _, v := range tests {
+		c, err := parseCatchUp(v.in)
+		if (err == nil) != v.valid {
+			t.Errorf("%q Expected valid: %v Got: %v", v.in, v.valid, err)
+			continue
+		}
+		if v.valid && (c.policy != v.policy || c.within != v.within) {
+			t.Errorf("%q Expected: %v %v Got: %v %v", v.in, v.policy, v.within, c.policy, c.within)
+		}
+	}
+}
+
+func Test_Missed(t *testing.T) {
+	daily, _ := cron.Parse("0 0 4 * * *")
+	now := time.Date(2017, 5, 3, 7, 0, 0, 0, time.Local)
+	at := time.Date(2017, 5, 3, 4, 0, 0, 0, time.Local)
+	var tests = []struct {
+		catchUp  string
+		last     time.Time
+		expected time.Time
+	}{
+		{"skip", now.Add(-time.Hour * 48), time.Time{}},
+		{"run-once-on-start", now.Add(-time.Hour * 48), at.Add(-time.Hour * 24)},
+		{"run-once-on-start", at, time.Time{}},
+		{"run-if-within 4h", now.Add(-time.Hour * 48), at},
+		{"run-if-within 2h", now.Add(-time.Hour * 48), time.Time{}},
+	}
+	for _, v := range tests {
+		c, _ := parseCatchUp(v.catchUp)
+		jb := &job{schedule: daily, catchUp: c}
+		if res := jb.missed(v.last, now); !res.Equal(v.expected) {
+			t.Errorf("%q after %v Expected: %v Got: %v", v.catchUp, v.last, v.expected, res)
+		}
+	}
+}
+
+func Test_CatchUp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procwatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+	broadcast := SchedulerEntity{Name: "hello", Command: "say -1 Hello", Every: "1h", CatchUp: "run-once-on-start"}
+	schedule := Schedule{Schedule: []SchedulerEntity{broadcast}}
+
+	w := New(Cfg{UseScheduler: true, StatePath: path})
+	if _, err := w.Reload(schedule); err != nil {
+		t.Fatal(err)
+	}
+	w.jobs.cron.Stop()
+	if w.state.get("hello").Since.IsZero() {
+		t.Fatal("Expected Job to be tracked")
+	}
+
+	//pretend gorcon-arma was stopped two hours ago
+	w.state.jobs["hello"] = jobState{Since: time.Now().Add(-time.Hour * 3), Last: time.Now().Add(-time.Hour * 2)}
+	w.state.save()
+
+	w = New(Cfg{UseScheduler: true, StatePath: path})
+	if _, err := w.Reload(schedule); err != nil {
+		t.Fatal(err)
+	}
+	w.jobs.cron.Stop()
+	go w.catchUp(0)
+	select {
+	case cmd := <-w.cmdChan:
+		if cmd != "say -1 Hello" {
+			t.Error("Expected Command Got:", cmd)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected missed Job to be caught up")
+	}
+	time.Sleep(time.Millisecond * 50)
+	if last := w.state.get("hello").Last; time.Since(last) > time.Minute {
+		t.Error("Expected Catch-up to be recorded Got:", last)
+	}
+}
+
+func Test_StateKey(t *testing.T) {
+	broadcast := SchedulerEntity{Command: "say -1 Hello", Every: "1h", CatchUp: "run-once-on-start"}
+	restart := SchedulerEntity{Restart: true, Every: "24h", CatchUp: "run-once-on-start"}
+	w := New(Cfg{UseScheduler: true})
+	if _, err := w.Reload(Schedule{Schedule: []SchedulerEntity{broadcast, restart}}); err != nil {
+		t.Fatal(err)
+	}
+	w.jobs.cron.Stop()
+	last := time.Now().Add(-time.Hour * 2)
+	w.state.fired(w.jobs.list[0].stateKey(), last)
+
+	//inserting an entry in front must not move the state of the broadcast to another entry
+	if _, err := w.Reload(Schedule{Schedule: []SchedulerEntity{restart, broadcast}}); err != nil {
+		t.Fatal(err)
+	}
+	w.jobs.cron.Stop()
+	if got := w.state.get(w.jobs.list[1].stateKey()).Last; !got.Equal(last) {
+		t.Error("Expected State of Broadcast to be kept Got:", got)
+	}
+	if got := w.state.get(w.jobs.list[0].stateKey()).Last; !got.IsZero() {
+		t.Error("Expected no State for Restart Got:", got)
+	}
+}
@@ -0,0 +1,328 @@
+package procwatch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Conditions are checked when an entry fires, its action is skipped unless all of them are met
+type Conditions struct {
+	MinPlayers    int    `json:"minPlayers,omitempty"`
+	MaxPlayers    *int   `json:"maxPlayers,omitempty"`
+	OnlyIfEmpty   bool   `json:"onlyIfEmpty,omitempty"`
+	EmptyFor      string `json:"emptyFor,omitempty"`
+	RconConnected bool   `json:"rconConnected,omitempty"`
+	//If is an expression over players, uptime, empty and hour like "players == 0 && uptime > 6h"
+	If string `json:"if,omitempty"`
+}
+
+//String lists the set conditions
+func (c Conditions) String() string {
+	var list []string
+	if c.RconConnected {
+		list = append(list, "rconConnected")
+	}
+	if c.MinPlayers > 0 {
+		list = append(list, fmt.Sprintf("minPlayers %d", c.MinPlayers))
+	}
+	if c.MaxPlayers != nil {
+		list = append(list, fmt.Sprintf("maxPlayers %d", *c.MaxPlayers))
+	}
+	if c.OnlyIfEmpty {
+		list = append(list, "onlyIfEmpty")
+	}
+	if c.EmptyFor != "" {
+		list = append(list, "emptyFor "+c.EmptyFor)
+	}
+	if c.If != "" {
+		list = append(list, fmt.Sprintf("if %q", c.If))
+	}
+	return strings.Join(list, ", ")
+}
+
+//ErrNoRcon is returned for conditions requiring RCon without a connection
+var ErrNoRcon = errors.New("RCon is not connected")
+
+//condition checks Conditions against the server State
+type condition struct {
+	Conditions
+	emptyFor time.Duration
+	expr     expr
+}
+
+//newCondition validates c
+func newCondition(c Conditions) (*condition, error) {
+	cond := &condition{Conditions: c}
+	if c.EmptyFor != "" {
+		d, err := time.ParseDuration(c.EmptyFor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid emptyFor: %v", err)
+		}
+		cond.emptyFor = d
+	}
+	if c.If != "" {
+		e, err := parseExpr(c.If)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition %q: %v", c.If, err)
+		}
+		cond.expr = e
+	}
+	return cond, nil
+}
+
+//needsRcon returns whether checking requires the player list
+func (c *condition) needsRcon() bool {
+	return c.MinPlayers > 0 || c.MaxPlayers != nil || c.OnlyIfEmpty || c.emptyFor > 0 || c.RconConnected || c.expr != nil
+}
+
+//State of the server conditions are evaluated against
+type State struct {
+	Connected bool
+	Players   int
+	Uptime    time.Duration
+	Empty     time.Duration
+	Hour      int
+}
+
+//check returns an error describing the first unmet condition
+func (c *condition) check(s State) error {
+	if !c.needsRcon() {
+		return nil
+	}
+	if !s.Connected {
+		return ErrNoRcon
+	}
+	switch {
+	case s.Players < c.MinPlayers:
+		return fmt.Errorf("%d players online, at least %d required", s.Players, c.MinPlayers)
+	case c.MaxPlayers != nil && s.Players > *c.MaxPlayers:
+		return fmt.Errorf("%d players online, at most %d allowed", s.Players, *c.MaxPlayers)
+	case c.OnlyIfEmpty && s.Players > 0:
+		return fmt.Errorf("%d players online, server is not empty", s.Players)
+	case c.emptyFor > 0 && s.Empty < c.emptyFor:
+		return fmt.Errorf("server empty for %v, %v required", s.Empty, c.emptyFor)
+	case c.expr != nil && c.expr(s.vars()) == 0:
+		return fmt.Errorf("%q is false", c.If)
+	}
+	return nil
+}
+
+func (s State) vars() map[string]float64 {
+	return map[string]float64{
+		"players": float64(s.Players),
+		"uptime":  s.Uptime.Seconds(),
+		"empty":   s.Empty.Seconds(),
+		"hour":    float64(s.Hour),
+	}
+}
+
+//State queries the current server state over RCon
+func (w *Watcher) State() State {
+	s := State{Uptime: time.Since(w.created)}
+	if started := w.Started(); !started.IsZero() {
+		s.Uptime = time.Since(started)
+	}
+	if loc, err := loadLocation(w.timezone); err == nil {
+		s.Hour = time.Now().In(loc).Hour()
+	}
+	if w.querier == nil {
+		return s
+	}
+	list, err := players.List(w.querier)
+	if err != nil {
+		glog.V(2).Infoln("Could not query Players:", err)
+		return s
+	}
+	s.Connected = true
+	s.Players = len(list)
+	w.emptyLock.Lock()
+	defer w.emptyLock.Unlock()
+	if s.Players > 0 {
+		w.emptySince = time.Time{}
+	} else if w.emptySince.IsZero() {
+		w.emptySince = time.Now()
+	}
+	if !w.emptySince.IsZero() {
+		s.Empty = time.Since(w.emptySince)
+	}
+	return s
+}
+
+//trackEmpty samples the player count so the time the server has been empty is known
+func (w *Watcher) trackEmpty(interval time.Duration) {
+	for {
+		glog.V(10).Infoln("Looping in trackEmpty")
+		w.State()
+		time.Sleep(interval)
+	}
+}
+
+//expr is a compiled condition expression, booleans are represented as 1 and 0
+type expr func(vars map[string]float64) float64
+
+//exprParser is a recursive descent parser for condition expressions
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+//parseExpr compiles expressions like "players == 0 && (uptime > 6h || hour < 6)"
+func parseExpr(s string) (expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("()", r):
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("=!<>&|", r):
+			j := i + 1
+			for j < len(s) && j < i+2 && strings.ContainsRune("=&|", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) or() (expr, error) {
+	left, err := p.and()
+	for err == nil && p.peek() == "||" {
+		p.pos++
+		var right expr
+		if right, err = p.and(); err == nil {
+			l := left
+			left = func(v map[string]float64) float64 { return truth(l(v) != 0 || right(v) != 0) }
+		}
+	}
+	return left, err
+}
+
+func (p *exprParser) and() (expr, error) {
+	left, err := p.not()
+	for err == nil && p.peek() == "&&" {
+		p.pos++
+		var right expr
+		if right, err = p.not(); err == nil {
+			l := left
+			left = func(v map[string]float64) float64 { return truth(l(v) != 0 && right(v) != 0) }
+		}
+	}
+	return left, err
+}
+
+func (p *exprParser) not() (expr, error) {
+	if p.peek() == "!" {
+		p.pos++
+		e, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return func(v map[string]float64) float64 { return truth(e(v) == 0) }, nil
+	}
+	return p.comparison()
+}
+
+func (p *exprParser) comparison() (expr, error) {
+	left, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	compare, ok := map[string]func(a, b float64) bool{
+		"==": func(a, b float64) bool { return a == b },
+		"!=": func(a, b float64) bool { return a != b },
+		"<":  func(a, b float64) bool { return a < b },
+		"<=": func(a, b float64) bool { return a <= b },
+		">":  func(a, b float64) bool { return a > b },
+		">=": func(a, b float64) bool { return a >= b },
+	}[op]
+	if !ok {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	return func(v map[string]float64) float64 { return truth(compare(left(v), right(v))) }, nil
+}
+
+func (p *exprParser) primary() (expr, error) {
+	token := p.peek()
+	p.pos++
+	switch {
+	case token == "":
+		return nil, errors.New("unexpected end")
+	case token == "(":
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return e, nil
+	case token == "players" || token == "uptime" || token == "empty" || token == "hour":
+		return func(v map[string]float64) float64 { return v[token] }, nil
+	}
+	if n, err := strconv.ParseFloat(token, 64); err == nil {
+		return func(map[string]float64) float64 { return n }, nil
+	}
+	if d, err := time.ParseDuration(token); err == nil {
+		seconds := d.Seconds()
+		return func(map[string]float64) float64 { return seconds }, nil
+	}
+	return nil, fmt.Errorf("unknown value %q", token)
+}
+
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package procwatch
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_ParseExpr(t *testing.T) {
+	vars := State{Players: 0, Uptime: time.Hour * 7, Empty: time.Minute * 40, Hour: 3}.vars()
+	var tests = []struct {
+		expr     string
+		expected bool
+	}{
+		{"players == 0", true},
+		{"players > 0", false},
+		{"uptime > 6h", true},
+		{"players == 0 && uptime > 6h", true},
+		{"players > 0 || empty >= 30m", true},
+		{"!(hour < 6) || players != 0", false},
+		{"(hour >= 2 && hour < 6) && empty > 1800", true},
+	}
+	for _, v := range tests {
+		e, err := parseExpr(v.expr)
+		if err != nil {
+			t.Errorf("%q: %v", v.expr, err)
+			continue
+		}
+		if res := e(vars) != 0; res != v.expected {
+			t.Errorf("%q Expected: %v Got: %v", v.expr, v.expected, res)
+		}
+	}
+	for _, invalid := range []string{"", "players ==", "players = 0", "(players > 0", "admins > 0", "players > 0 $"} {
+		if _, err := parseExpr(invalid); err == nil {
+			t.Errorf("%q: Expected Error", invalid)
+		}
+	}
+}
+
+func Test_ConditionCheck(t *testing.T) {
+	zero := 0
+	var tests = []struct {
+		conditions Conditions
+		state      State
+		met        bool
+	}{
+		{Conditions{}, State{}, true},
+		{Conditions{RconConnected: true}, State{}, false},
+		{Conditions{RconConnected: true}, State{Connected: true}, true},
+		{Conditions{MinPlayers: 1}, State{Connected: true}, false},
+		{Conditions{MaxPlayers: &zero}, State{Connected: true, Players: 1}, false},
+		{Conditions{OnlyIfEmpty: true}, State{Connected: true}, true},
+		{Conditions{EmptyFor: "30m"}, State{Connected: true, Empty: time.Minute * 10}, false},
+		{Conditions{EmptyFor: "30m"}, State{Connected: true, Empty: time.Minute * 31}, true},
+		{Conditions{If: "hour < 6"}, State{Connected: true, Hour: 12}, false},
+	}
+	for _, v := range tests {
+		c, err := newCondition(v.conditions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.check(v.state); (err == nil) != v.met {
+			t.Errorf("%+v %+v Expected met: %v Got: %v", v.conditions, v.state, v.met, err)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// +build !windows
+
+package procwatch
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+//setProcessGroup starts cmd in its own process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+//killProcessGroup kills cmd and every process it started
+func killProcessGroup(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
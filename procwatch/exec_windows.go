@@ -0,0 +1,11 @@
+package procwatch
+
+import "os/exec"
+
+//setProcessGroup is not needed on windows
+func setProcessGroup(cmd *exec.Cmd) {}
+
+//killProcessGroup kills cmd, children keep running but can not block the action
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
@@ -0,0 +1,241 @@
+package procwatch
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/robfig/cron"
+)
+
+//ErrJobNotFound is returned for unknown job IDs
+var ErrJobNotFound = errors.New("Job not found")
+
+//job is a scheduled entry with its runtime state
+type job struct {
+	id       string
+	entry    SchedulerEntity
+	schedule cron.Schedule
+	cond     *condition
+	catchUp  catchUp
+	action   func() error
+
+	sync.Mutex
+	paused  bool
+	skip    bool
+	prev    time.Time
+	outcome string
+}
+
+//JobInfo describes the state of a scheduled job
+type JobInfo struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Action      string    `json:"action"`
+	Conditions  string    `json:"conditions,omitempty"`
+	CatchUp     string    `json:"catchUp,omitempty"`
+	Next        time.Time `json:"next"`
+	Prev        time.Time `json:"prev"`
+	Outcome     string    `json:"outcome,omitempty"`
+	Paused      bool      `json:"paused"`
+	SkipNext    bool      `json:"skipNext"`
+}
+
+//JobList describes the state of the scheduler and all of its jobs
+type JobList struct {
+	//Paused is set if the whole scheduler is paused
+	Paused bool      `json:"paused"`
+	Jobs   []JobInfo `json:"jobs"`
+}
+
+//fire runs the action of jb unless it is paused, skipped or its conditions are not met
+func (w *Watcher) fire(jb *job) {
+	w.state.fired(jb.stateKey(), time.Now())
+	if reason := w.suppressed(jb); reason != "" {
+		jb.Lock()
+		if !jb.paused && !w.Paused() {
+			jb.skip = false
+		}
+		jb.Unlock()
+		w.record(jb, "skipped: "+reason)
+		glog.Infof("Skipping %v: %v", jb.entry, reason)
+		return
+	}
+	if jb.cond.needsRcon() {
+		if err := jb.cond.check(w.State()); err != nil {
+			w.record(jb, "skipped: "+err.Error())
+			glog.Infof("Skipping %v: %v", jb.entry, err)
+			return
+		}
+	}
+	w.run(jb)
+}
+
+//run the action of jb and record its outcome
+func (w *Watcher) run(jb *job) {
+	if err := jb.action(); err != nil {
+		glog.Errorf("Job %v failed: %v", jb.entry, err)
+		w.record(jb, "failed: "+err.Error())
+		return
+	}
+	glog.Infof("Job %v succeeded", jb.entry)
+	w.record(jb, "succeeded")
+}
+
+//record the outcome of the latest occurrence of jb
+func (w *Watcher) record(jb *job, outcome string) {
+	jb.Lock()
+	jb.prev = time.Now()
+	jb.outcome = outcome
+	jb.Unlock()
+}
+
+//suppressed returns why the next occurrence of jb must not run or an empty string
+func (w *Watcher) suppressed(jb *job) string {
+	if w.Paused() {
+		return "scheduler paused"
+	}
+	jb.Lock()
+	defer jb.Unlock()
+	switch {
+	case jb.paused:
+		return "job paused"
+	case jb.skip:
+		return "occurrence skipped"
+	}
+	return ""
+}
+
+//keepState copies the state of jobs with the same ID from old
+func (j *jobs) keepState(old *jobs) {
+	for _, jb := range j.list {
+		prev, ok := old.byID[jb.id]
+		if !ok || prev.entry.key() != jb.entry.key() {
+			continue
+		}
+		prev.Lock()
+		jb.paused, jb.skip, jb.prev, jb.outcome = prev.paused, prev.skip, prev.prev, prev.outcome
+		prev.Unlock()
+	}
+}
+
+//Jobs lists all scheduled jobs in schedule order, Next is zero while a job or the scheduler is paused
+func (w *Watcher) Jobs() JobList {
+	w.jobsLock.RLock()
+	defer w.jobsLock.RUnlock()
+	list := JobList{Paused: w.Paused()}
+	if w.jobs == nil {
+		return list
+	}
+	now := time.Now()
+	list.Jobs = make([]JobInfo, 0, len(w.jobs.list))
+	for _, jb := range w.jobs.list {
+		jb.Lock()
+		var next time.Time
+		if !jb.paused && !list.Paused {
+			next = jb.next(now, jb.skip)
+		}
+		list.Jobs = append(list.Jobs, JobInfo{
+			ID:          jb.id,
+			Description: jb.entry.String(),
+			Action:      jb.entry.action(),
+			Conditions:  jb.entry.Conditions.String(),
+			CatchUp:     jb.entry.CatchUp,
+			Next:        next,
+			Prev:        jb.prev,
+			Outcome:     jb.outcome,
+			Paused:      jb.paused,
+			SkipNext:    jb.skip,
+		})
+		jb.Unlock()
+	}
+	return list
+}
+
+//next returns the next fire time of jb after now, passing over a skipped occurrence
+func (jb *job) next(now time.Time, skip bool) time.Time {
+	t := jb.schedule.Next(now)
+	if skip && !t.IsZero() {
+		t = jb.schedule.Next(t)
+	}
+	return t
+}
+
+//job returns the job with the given id
+func (w *Watcher) job(id string) (*job, error) {
+	w.jobsLock.RLock()
+	defer w.jobsLock.RUnlock()
+	if w.jobs == nil {
+		return nil, ErrSchedulerDisabled
+	}
+	jb, ok := w.jobs.byID[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return jb, nil
+}
+
+//Trigger runs the action of a job immediately, ignoring its conditions and pause state
+func (w *Watcher) Trigger(id string) error {
+	jb, err := w.job(id)
+	if err != nil {
+		return err
+	}
+	glog.Infof("Triggering %v", jb.entry)
+	go w.run(jb)
+	return nil
+}
+
+//Pause a job or the whole scheduler if id is empty
+func (w *Watcher) Pause(id string) error {
+	return w.setPaused(id, true)
+}
+
+//Resume a job or the whole scheduler if id is empty
+func (w *Watcher) Resume(id string) error {
+	return w.setPaused(id, false)
+}
+
+func (w *Watcher) setPaused(id string, paused bool) error {
+	if id == "" {
+		if !w.useScheduler {
+			return ErrSchedulerDisabled
+		}
+		var v int32
+		if paused {
+			v = 1
+		}
+		atomic.StoreInt32(&w.paused, v)
+		glog.Infof("Scheduler paused: %v", paused)
+		return nil
+	}
+	jb, err := w.job(id)
+	if err != nil {
+		return err
+	}
+	jb.Lock()
+	jb.paused = paused
+	jb.Unlock()
+	glog.Infof("Job %v paused: %v", jb.entry, paused)
+	return nil
+}
+
+//Paused returns whether the whole scheduler is paused
+func (w *Watcher) Paused() bool {
+	return atomic.LoadInt32(&w.paused) == 1
+}
+
+//SkipNext skips the next occurrence of a job, a restart skips its countdown warnings as well
+func (w *Watcher) SkipNext(id string) error {
+	jb, err := w.job(id)
+	if err != nil {
+		return err
+	}
+	jb.Lock()
+	jb.skip = true
+	jb.Unlock()
+	glog.Infof("Skipping next occurrence of %v", jb.entry)
+	return nil
+}
@@ -0,0 +1,74 @@
+package procwatch
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Jobs(t *testing.T) {
+	w := New(Cfg{UseScheduler: true})
+	broadcast := SchedulerEntity{Name: "hello", Command: "say -1 Hello", Every: "5m"}
+	restart := SchedulerEntity{Restart: true, Day: "*", Hour: "6", Minute: "0"}
+	if _, err := w.Reload(Schedule{Schedule: []SchedulerEntity{broadcast, restart}}); err != nil {
+		t.Fatal(err)
+	}
+	defer w.jobs.cron.Stop()
+
+	infos := w.Jobs().Jobs
+	if len(infos) != 2 || infos[0].ID != "hello" || infos[1].ID != "2" {
+		t.Fatal("Unexpected Jobs:", infos)
+	}
+	if err := w.Trigger("missing"); err != ErrJobNotFound {
+		t.Error("Expected ErrJobNotFound Got:", err)
+	}
+	if err := w.Trigger("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if cmd := <-w.cmdChan; cmd != "say -1 Hello" {
+		t.Error("Expected Command Got:", cmd)
+	}
+
+	next := w.NextRestart()
+	if err := w.SkipNext("2"); err != nil {
+		t.Fatal(err)
+	}
+	if skipped := w.NextRestart(); !skipped.Equal(next.Add(time.Hour * 24)) {
+		t.Error("Expected skipped Restart at", next.Add(time.Hour*24), "Got:", skipped)
+	}
+	if info := w.Jobs().Jobs[1]; !info.Next.Equal(next.Add(time.Hour * 24)) {
+		t.Error("Expected Next to pass over skipped Restart Got:", info.Next)
+	}
+	jb, _ := w.job("2")
+	w.fire(jb)
+	if info := w.Jobs().Jobs[1]; info.SkipNext || info.Outcome != "skipped: occurrence skipped" {
+		t.Error("Expected consumed Skip Got:", info)
+	}
+
+	if err := w.Pause("hello"); err != nil {
+		t.Fatal(err)
+	}
+	jb, _ = w.job("hello")
+	w.fire(jb)
+	if info := w.Jobs().Jobs[0]; !info.Paused || info.Outcome != "skipped: job paused" {
+		t.Error("Expected paused Job Got:", info)
+	}
+
+	if err := w.Pause(""); err != nil {
+		t.Fatal(err)
+	}
+	if !w.NextRestart().IsZero() {
+		t.Error("Expected no Restart while paused")
+	}
+	if list := w.Jobs(); !list.Paused || !list.Jobs[1].Next.IsZero() {
+		t.Error("Expected paused Scheduler Got:", list)
+	}
+	w.Resume("")
+	w.Resume("hello")
+
+	if _, err := w.Reload(Schedule{Schedule: []SchedulerEntity{broadcast}}); err != nil {
+		t.Fatal(err)
+	}
+	if info := w.Jobs().Jobs[0]; info.Outcome != "skipped: job paused" {
+		t.Error("Expected State to survive Reload Got:", info)
+	}
+}
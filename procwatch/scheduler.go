@@ -2,14 +2,21 @@ package procwatch
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
-
-	"syscall"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/robfig/cron"
 )
 
+//ErrSchedulerDisabled is returned when reloading the schedule of a Watcher without scheduler
+var ErrSchedulerDisabled = errors.New("Scheduler is disabled")
+
 //SchedulePath to config
 type SchedulePath string
 
@@ -19,12 +26,43 @@ type Schedule struct {
 }
 
 //SchedulerEntity all data required by Procwatch
+//The time is given by exactly one of day/hour/minute, cron, every or at
 type SchedulerEntity struct {
-	Command string `json:"command"`
-	Restart bool   `json:"restart"`
-	Day     string `json:"day"`
-	Hour    string `json:"hour"`
-	Minute  string `json:"minute"`
+	Name     string `json:"name,omitempty"`
+	Command  string `json:"command"`
+	Restart  bool   `json:"restart"`
+	Day      string `json:"day,omitempty"`
+	Hour     string `json:"hour,omitempty"`
+	Minute   string `json:"minute,omitempty"`
+	Cron     string `json:"cron,omitempty"`
+	Every    string `json:"every,omitempty"`
+	At       string `json:"at,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	//CatchUp is the policy for occurrences missed while gorcon-arma was not running
+	CatchUp string `json:"catchUp,omitempty"`
+	Conditions
+	Task
+	//Warnings are the minutes before a restart a countdown message is broadcasted
+	Warnings []int  `json:"warnings"`
+	Message  string `json:"message"`
+}
+
+//DefaultWarning is the countdown message used by restart entries without message
+const DefaultWarning = "Server restart in {minutes} minutes"
+
+//OffsetSchedule fires Offset before the wrapped Schedule so it moves along with it
+type OffsetSchedule struct {
+	Schedule cron.Schedule
+	Offset   time.Duration
+}
+
+//Next returns the next activation time later than t
+func (o OffsetSchedule) Next(t time.Time) time.Time {
+	next := o.Schedule.Next(t.Add(o.Offset))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(-o.Offset)
 }
 
 //Parse json from path and return Schedule
@@ -44,49 +82,329 @@ func parseConfig(content []byte) (*Schedule, error) {
 	return config, nil
 }
 
-func (w *Watcher) buildJobs() error {
-	scheduleArr := w.schedule.Schedule
-	glog.V(1).Infoln("Scheduling Commands: ")
-	for index := 0; index < len(scheduleArr); index++ {
-		scheduleEntry := scheduleArr[index]
-		command := scheduleEntry.Command
-		restart := scheduleEntry.Restart
-		day := scheduleEntry.Day
-		hour := scheduleEntry.Hour
-		minute := scheduleEntry.Minute
-		glog.V(1).Infof("Adding Event at %s %s * * %s", minute, hour, day)
-		if restart {
-			err := w.cron.AddFunc(fmt.Sprintf("0 %s %s * * %s", minute, hour, day), func() {
-				if w.useWatcher {
-					glog.V(2).Infoln("Sending Termination Signal to Process")
-					err := w.cmd.Process.Signal(syscall.SIGTERM)
-					if err != nil {
-						if err.Error() != "not supported by windows" {
-							glog.Error(err)
-						}
-						err := w.cmd.Process.Signal(syscall.SIGKILL)
-						if err != nil {
-							glog.Error(err)
-						}
-					}
-				} else {
-					glog.V(2).Infoln("Sending Restart Command to Channel")
-					w.cmdChan <- "#restartserver"
+//String describes the entry
+func (e SchedulerEntity) String() string {
+	switch e.kind() {
+	case ActionCommand:
+		return fmt.Sprintf("%q %s", e.Command, e.when())
+	case ActionExec:
+		return fmt.Sprintf("exec %q %s", strings.Join(e.Exec, " "), e.when())
+	case ActionHTTP:
+		return fmt.Sprintf("http %s %s", e.URL, e.when())
+	default:
+		return e.kind() + " " + e.when()
+	}
+}
+
+//Preview describes a validated entry and its upcoming fire times
+type Preview struct {
+	Entry      SchedulerEntity
+	Action     string
+	Conditions string
+	Next       []time.Time
+	Err        error
+}
+
+//Preview validates all entries like a running scheduler with or without watcher
+//and lists the next n fire times after from in timezone
+//Invalid entries are reported in the Err of their Preview, the error is only set for an invalid timezone
+func (s Schedule) Preview(timezone string, useWatcher bool, n int, from time.Time) ([]Preview, error) {
+	loc, err := loadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	list, errs := s.validate(loc, useWatcher, from)
+	previews := make([]Preview, len(s.Schedule))
+	for i, jb := range list {
+		p := Preview{Entry: jb.entry, Action: jb.entry.action(), Conditions: jb.entry.Conditions.String(), Err: errs[i]}
+		if p.Err == nil {
+			t := from.In(loc)
+			for len(p.Next) < n {
+				if t = jb.schedule.Next(t); t.IsZero() {
+					break
 				}
-			})
-			if err != nil {
-				return err
+				p.Next = append(p.Next, t.In(loc))
 			}
-		} else {
-			err := w.cron.AddFunc(fmt.Sprintf("0 %s %s * * %s", minute, hour, day), func() {
-				glog.V(2).Infoln("Sending Command to Channel: ", command)
-				w.cmdChan <- command
-			})
-			if err != nil {
-				return err
+		}
+		previews[i] = p
+	}
+	return previews, nil
+}
+
+//validate checks every entry of the schedule evaluated in loc and builds their jobs without action
+//Running and previewed schedules share it, errs holds the error of every invalid entry
+//Intervals count from start
+func (s Schedule) validate(loc *time.Location, useWatcher bool, start time.Time) (list []*job, errs []error) {
+	list = make([]*job, len(s.Schedule))
+	errs = make([]error, len(s.Schedule))
+	ids := make(map[string]bool)
+	for index, entry := range s.Schedule {
+		jb := &job{id: entry.Name, entry: entry}
+		if jb.id == "" {
+			jb.id = fmt.Sprint(index + 1)
+		}
+		list[index] = jb
+		if ids[jb.id] {
+			errs[index] = fmt.Errorf("duplicate name %q", jb.id)
+			continue
+		}
+		ids[jb.id] = true
+		errs[index] = jb.parse(loc, useWatcher, start)
+	}
+	return list, errs
+}
+
+//parse the schedule, conditions and catch-up policy of the entry of jb and check its action
+func (jb *job) parse(loc *time.Location, useWatcher bool, start time.Time) (err error) {
+	if jb.schedule, err = jb.entry.schedule(loc, start); err != nil {
+		return err
+	}
+	if jb.cond, err = newCondition(jb.entry.Conditions); err != nil {
+		return err
+	}
+	if jb.catchUp, err = parseCatchUp(jb.entry.CatchUp); err != nil {
+		return err
+	}
+	return jb.entry.checkAction(useWatcher)
+}
+
+//jobs are the cron jobs built from a Schedule
+type jobs struct {
+	cron *cron.Cron
+	list []*job
+	byID map[string]*job
+}
+
+//newJobs validates schedule and builds its jobs without starting them
+func (w *Watcher) newJobs(schedule Schedule) (*jobs, error) {
+	loc, err := loadLocation(w.timezone)
+	if err != nil {
+		return nil, err
+	}
+	list, errs := schedule.validate(loc, w.useWatcher, time.Now())
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", list[i].entry, err)
+		}
+	}
+	w.jobsLock.RLock()
+	old := w.jobs
+	w.jobsLock.RUnlock()
+	for _, jb := range list {
+		w.anchor(jb, old)
+	}
+	j := &jobs{cron: cron.New(), byID: make(map[string]*job)}
+	glog.V(1).Infoln("Scheduling Commands: ")
+	for _, jb := range list {
+		jb := jb
+		glog.V(1).Infof("Adding Event %v", jb.entry)
+		jb.action = w.newAction(jb.entry)
+		j.list = append(j.list, jb)
+		j.byID[jb.id] = jb
+		j.cron.Schedule(jb.schedule, cron.FuncJob(func() { w.fire(jb) }))
+		if jb.entry.kind() == ActionRestart {
+			w.scheduleWarnings(j.cron, jb)
+		}
+	}
+	return j, nil
+}
+
+//anchor keeps the intervals of jb in step across reloads and restarts
+//Unchanged entries keep their schedule, others count from their last persisted run
+func (w *Watcher) anchor(jb *job, old *jobs) {
+	interval, ok := jb.schedule.(intervalSchedule)
+	if !ok {
+		return
+	}
+	if old != nil {
+		if prev, ok := old.byID[jb.id]; ok && prev.entry.key() == jb.entry.key() {
+			jb.schedule = prev.schedule
+			return
+		}
+	}
+	if last := w.state.get(jb.stateKey()).Last; !last.IsZero() && last.Before(interval.start) {
+		interval.start = last.Truncate(time.Second)
+		jb.schedule = interval
+	}
+}
+
+func (w *Watcher) buildJobs() error {
+	_, err := w.Reload(w.schedule)
+	return err
+}
+
+//Diff lists the entries added and removed by a reload
+type Diff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+//String summarizes the Diff
+func (d Diff) String() string {
+	return fmt.Sprintf("%d added, %d removed", len(d.Added), len(d.Removed))
+}
+
+//Reload validates schedule and atomically replaces the running jobs with it
+//Jobs already running, like a restart in progress, are not interrupted
+func (w *Watcher) Reload(schedule Schedule) (Diff, error) {
+	if !w.useScheduler {
+		return Diff{}, ErrSchedulerDisabled
+	}
+	j, err := w.newJobs(schedule)
+	if err != nil {
+		return Diff{}, err
+	}
+	w.jobsLock.Lock()
+	old, oldSchedule := w.jobs, w.schedule
+	w.jobs, w.schedule = j, schedule
+	if old != nil {
+		old.cron.Stop()
+		j.keepState(old)
+	}
+	j.cron.Start()
+	w.jobsLock.Unlock()
+	w.state.track(j)
+
+	var diff Diff
+	if old != nil {
+		diff = diffSchedules(oldSchedule, schedule)
+	} else {
+		diff = diffSchedules(Schedule{}, schedule)
+	}
+	for _, e := range diff.Removed {
+		glog.Infoln("Schedule: removed", e)
+	}
+	for _, e := range diff.Added {
+		glog.Infoln("Schedule: added", e)
+	}
+	return diff, nil
+}
+
+//diffSchedules compares the entries of both schedules
+func diffSchedules(old, new Schedule) Diff {
+	count := func(s Schedule) map[string]int {
+		m := make(map[string]int)
+		for _, e := range s.Schedule {
+			m[e.key()]++
+		}
+		return m
+	}
+	oldKeys, newKeys := count(old), count(new)
+	var diff Diff
+	for _, e := range old.Schedule {
+		if newKeys[e.key()] > 0 {
+			newKeys[e.key()]--
+			continue
+		}
+		diff.Removed = append(diff.Removed, e.String())
+	}
+	for _, e := range new.Schedule {
+		if oldKeys[e.key()] > 0 {
+			oldKeys[e.key()]--
+			continue
+		}
+		diff.Added = append(diff.Added, e.String())
+	}
+	return diff
+}
+
+//key identifies an entry by all of its fields
+func (e SchedulerEntity) key() string {
+	content, _ := json.Marshal(e)
+	return string(content)
+}
+
+//WatchSchedule reloads the schedule whenever its file changed
+func (w *Watcher) WatchSchedule(sc SchedulePath, interval time.Duration) {
+	modTime := time.Now()
+	if info, err := os.Stat(string(sc)); err == nil {
+		modTime = info.ModTime()
+	}
+	for {
+		glog.V(10).Infoln("Looping in WatchSchedule")
+		time.Sleep(interval)
+		info, err := os.Stat(string(sc))
+		if err != nil {
+			glog.Errorln("Could not check Schedule:", err)
+			continue
+		}
+		if !info.ModTime().After(modTime) {
+			continue
+		}
+		modTime = info.ModTime()
+		glog.Infoln("Schedule changed, reloading", sc)
+		if _, err := w.ReloadFile(sc); err != nil {
+			glog.Errorln("Keeping current Schedule:", err)
+		}
+	}
+}
+
+//ReloadFile parses the schedule at sc and reloads it
+func (w *Watcher) ReloadFile(sc SchedulePath) (Diff, error) {
+	schedule, err := sc.Parse()
+	if err != nil {
+		return Diff{}, err
+	}
+	diff, err := w.Reload(*schedule)
+	if err == nil {
+		glog.Infoln("Schedule reloaded:", diff)
+	}
+	return diff, err
+}
+
+//scheduleWarnings adds the countdown broadcasts of a restart job to c
+//Warnings are suppressed while the restart is paused or its next occurrence is skipped
+func (w *Watcher) scheduleWarnings(c *cron.Cron, restart *job) {
+	entry := restart.entry
+	text := entry.Message
+	if text == "" {
+		text = DefaultWarning
+	}
+	for _, minutes := range entry.Warnings {
+		if minutes <= 0 {
+			glog.Warningf("Ignoring restart warning %d minutes before %v", minutes, entry)
+			continue
+		}
+		offset := time.Minute * time.Duration(minutes)
+		command := "say -1 " + message.Render(text, message.Vars{"minutes": minutes})
+		glog.V(1).Infof("Adding Restart Warning %v before %v", offset, entry)
+		c.Schedule(OffsetSchedule{Schedule: restart.schedule, Offset: offset}, cron.FuncJob(func() {
+			if reason := w.suppressed(restart); reason != "" {
+				glog.V(2).Infof("Skipping Restart Warning of %v: %v", entry, reason)
+				return
+			}
+			glog.V(2).Infoln("Sending Command to Channel: ", command)
+			if !w.send(command) {
+				glog.Errorf("Restart Warning %v before %v failed: %q was not sent", offset, entry, command)
 			}
+		}))
+	}
+}
+
+//NextRestart returns the time of the next scheduled restart or zero time if there is none
+//Paused restarts are ignored and skipped occurrences are left out
+func (w *Watcher) NextRestart() time.Time {
+	w.jobsLock.RLock()
+	defer w.jobsLock.RUnlock()
+	if w.jobs == nil || w.Paused() {
+		return time.Time{}
+	}
+	now := time.Now()
+	var next time.Time
+	for _, jb := range w.jobs.list {
+		if jb.entry.kind() != ActionRestart {
+			continue
+		}
+		jb.Lock()
+		paused, skip := jb.paused, jb.skip
+		jb.Unlock()
+		if paused {
+			continue
+		}
+		t := jb.next(now, skip)
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
 		}
 	}
-	w.cron.Start()
-	return nil
+	return next
 }
@@ -0,0 +1,109 @@
+package procwatch
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+func Test_OffsetSchedule(t *testing.T) {
+	restart, err := cron.Parse("0 0 12 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	warning := OffsetSchedule{Schedule: restart, Offset: time.Minute * 30}
+	var tests = []struct {
+		now, expected time.Time
+	}{
+		{time.Date(2017, 5, 1, 9, 0, 0, 0, time.Local), time.Date(2017, 5, 1, 11, 30, 0, 0, time.Local)},
+		{time.Date(2017, 5, 1, 11, 45, 0, 0, time.Local), time.Date(2017, 5, 2, 11, 30, 0, 0, time.Local)},
+		{time.Date(2017, 5, 1, 11, 29, 59, 0, time.Local), time.Date(2017, 5, 1, 11, 30, 0, 0, time.Local)},
+	}
+	for _, v := range tests {
+		if res := warning.Next(v.now); !res.Equal(v.expected) {
+			t.Errorf("%v Expected: %v Got: %v", v.now, v.expected, res)
+		}
+	}
+}
+
+func Test_IntervalWarning(t *testing.T) {
+	start := time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC)
+	restart, err := SchedulerEntity{Restart: true, Every: "4h", Warnings: []int{30}}.schedule(time.UTC, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warning := OffsetSchedule{Schedule: restart, Offset: time.Minute * 30}
+	var tests = []struct {
+		now, restart, warning time.Time
+	}{
+		{start, start.Add(time.Hour * 4), start.Add(time.Minute * 210)},
+		{start.Add(time.Hour), start.Add(time.Hour * 4), start.Add(time.Minute * 210)},
+		{start.Add(time.Minute * 210), start.Add(time.Hour * 4), start.Add(time.Minute * 450)},
+		{start.Add(time.Hour * 4), start.Add(time.Hour * 8), start.Add(time.Minute * 450)},
+	}
+	for _, v := range tests {
+		if res := restart.Next(v.now); !res.Equal(v.restart) {
+			t.Errorf("%v Expected Restart: %v Got: %v", v.now, v.restart, res)
+		}
+		if res := warning.Next(v.now); !res.Equal(v.warning) {
+			t.Errorf("%v Expected Warning: %v Got: %v", v.now, v.warning, res)
+		}
+	}
+}
+
+func Test_ScheduleWarnings(t *testing.T) {
+	w := New(Cfg{})
+	c := cron.New()
+	restart, _ := cron.Parse("0 0 12 * * *")
+	w.scheduleWarnings(c, &job{schedule: restart, entry: SchedulerEntity{Hour: "12", Minute: "0", Warnings: []int{30, 5, 0}}})
+	entries := c.Entries()
+	if len(entries) != 2 {
+		t.Fatal("Expected 2 Warnings Got:", len(entries))
+	}
+	expected := []string{"say -1 Server restart in 30 minutes", "say -1 Server restart in 5 minutes"}
+	for i, entry := range entries {
+		go entry.Job.Run()
+		if cmd := <-w.cmdChan; cmd != expected[i] {
+			t.Error("Expected:", expected[i], "Got:", cmd)
+		}
+	}
+}
+
+func Test_Reload(t *testing.T) {
+	w := New(Cfg{UseScheduler: true})
+	broadcast := SchedulerEntity{Command: "say -1 Hello", Day: "*", Hour: "*", Minute: "*/5"}
+	restart := SchedulerEntity{Restart: true, Day: "*", Hour: "6", Minute: "0"}
+	diff, err := w.Reload(Schedule{Schedule: []SchedulerEntity{broadcast, restart}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Added) != 2 || len(diff.Removed) != 0 {
+		t.Error("Unexpected Diff:", diff)
+	}
+	if w.NextRestart().Hour() != 6 {
+		t.Error("Expected Restart at 6 Got:", w.NextRestart())
+	}
+
+	if _, err := w.Reload(Schedule{Schedule: []SchedulerEntity{{Day: "*", Hour: "25", Minute: "0"}}}); err == nil {
+		t.Error("Expected Error for invalid Schedule")
+	}
+	if len(w.jobs.cron.Entries()) != 2 {
+		t.Error("Expected invalid Schedule to keep the running Jobs")
+	}
+
+	restart.Hour = "18"
+	diff, err = w.Reload(Schedule{Schedule: []SchedulerEntity{broadcast, restart}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Diff{Added: []string{"restart at 0 0 18 * * *"}, Removed: []string{"restart at 0 0 6 * * *"}}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Error("Expected:", expected, "Got:", diff)
+	}
+	if w.NextRestart().Hour() != 18 {
+		t.Error("Expected Restart at 18 Got:", w.NextRestart())
+	}
+	w.jobs.cron.Stop()
+}
@@ -0,0 +1,122 @@
+package procwatch
+
+import (
+	"fmt"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Shutdown configures the restart pipeline
+//Enabled locks the server, kicks all players and sends #shutdown before the process gets signaled
+type Shutdown struct {
+	Enabled     bool
+	KickMessage string
+	KickDelay   time.Duration
+	ExitTimeout time.Duration
+	KillTimeout time.Duration
+}
+
+func (s Shutdown) withDefaults() Shutdown {
+	if s.KickMessage == "" {
+		s.KickMessage = "Server is restarting"
+	}
+	if s.ExitTimeout == 0 {
+		s.ExitTimeout = time.Minute
+	}
+	if s.KillTimeout == 0 {
+		s.KillTimeout = time.Second * 30
+	}
+	return s
+}
+
+//SetQuerier sets the RCon Connection used to list the players kicked before a restart
+//and to check the conditions of scheduled entries
+func (w *Watcher) SetQuerier(q players.Querier) {
+	w.querier = q
+	if w.useScheduler {
+		go w.trackEmpty(time.Minute)
+	}
+}
+
+//send a command to the command channel, giving up if nobody receives it
+func (w *Watcher) send(cmd string) bool {
+	select {
+	case w.cmdChan <- cmd:
+		return true
+	case <-time.After(time.Second * 5):
+		glog.Warningf("Restart: could not send %v, is RCon connected?", cmd)
+		return false
+	}
+}
+
+//prepareShutdown locks the server and kicks all players
+func (w *Watcher) prepareShutdown() {
+	if !w.shutdown.Enabled {
+		return
+	}
+	glog.Infoln("Restart: locking server")
+	w.send("#lock")
+	if w.querier == nil {
+		return
+	}
+	list, err := players.List(w.querier)
+	if err != nil {
+		glog.Warningln("Restart: could not list players to kick:", err)
+		return
+	}
+	glog.Infof("Restart: kicking %d players", len(list))
+	for _, p := range list {
+		w.send(fmt.Sprintf("kick %d %s", p.Number, w.shutdown.KickMessage))
+	}
+	if w.shutdown.KickDelay > 0 && len(list) > 0 {
+		time.Sleep(w.shutdown.KickDelay)
+	}
+}
+
+//shutdownProcess stops the watched process, escalating from #shutdown to SIGTERM and SIGKILL
+//The process is started again by wait once it exited, if it could not be killed the restart is given up
+func (w *Watcher) shutdownProcess() error {
+	cmd, exited := w.process()
+	if cmd == nil {
+		atomic.StoreInt32(&w.restarting, 0)
+		return ErrStopped
+	}
+	if w.shutdown.Enabled {
+		w.prepareShutdown()
+		glog.Infoln("Restart: sending #shutdown")
+		if w.send("#shutdown") && w.waitExit(exited, w.shutdown.ExitTimeout) {
+			return nil
+		}
+		glog.Warningf("Restart: server did not exit within %v", w.shutdown.ExitTimeout)
+	}
+	glog.Infoln("Restart: sending termination signal to process")
+	err := cmd.Process.Signal(syscall.SIGTERM)
+	if err == nil && w.waitExit(exited, w.shutdown.KillTimeout) {
+		return nil
+	}
+	if err != nil && err.Error() != "not supported by windows" {
+		glog.Error(err)
+	}
+	glog.Warningln("Restart: killing process")
+	if err := cmd.Process.Signal(syscall.SIGKILL); err != nil {
+		glog.Errorln("Restart: could not kill process:", err)
+		atomic.StoreInt32(&w.restarting, 0)
+		return fmt.Errorf("could not kill process: %v", err)
+	}
+	return nil
+}
+
+//waitExit returns whether the process exited within timeout
+func (w *Watcher) waitExit(exited chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-exited:
+		glog.Infoln("Restart: server exited")
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,45 @@
+package procwatch
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeQuerier string
+
+func (f fakeQuerier) Query(cmd string, timeout time.Duration) (string, error) {
+	return string(f), nil
+}
+
+func Test_RestartWithoutWatcher(t *testing.T) {
+	w := New(Cfg{Shutdown: Shutdown{Enabled: true, KickMessage: "Restart"}})
+	w.SetQuerier(fakeQuerier("0   127.0.0.1:2304   31   0123456789abcdef0123456789abcdef(OK) Steve\n" +
+		"4   10.0.0.12:2316   52   fedcba9876543210fedcba9876543210(OK) Kevin\n"))
+	go w.Restart()
+	var commands []string
+	for i := 0; i < 4; i++ {
+		commands = append(commands, <-w.cmdChan)
+	}
+	expected := []string{"#lock", "kick 0 Restart", "kick 4 Restart", "#restartserver"}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Error("Expected:", expected, "Got:", commands)
+	}
+}
+
+func Test_RestartKillFailed(t *testing.T) {
+	w := New(Cfg{UseWatcher: true, Shutdown: Shutdown{KillTimeout: time.Millisecond * 10}})
+	//a process which was already reaped can not be signaled
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skip(err)
+	}
+	w.proc.cmd, w.proc.exited = cmd, make(chan struct{})
+	if err := w.Restart(); err == nil {
+		t.Error("Expected Error for failed kill")
+	}
+	if err := w.Restart(); err == ErrRestarting {
+		t.Error("Expected failed Restart not to block further Restarts")
+	}
+}
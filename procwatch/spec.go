@@ -0,0 +1,174 @@
+package procwatch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+//atLayouts are the accepted formats of one-shot entries
+var atLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+//schedule returns the cron.Schedule of the entry, times are evaluated in loc unless the entry has its own timezone
+//Intervals count from start
+func (e SchedulerEntity) schedule(loc *time.Location, start time.Time) (cron.Schedule, error) {
+	if e.Timezone != "" {
+		var err error
+		if loc, err = loadLocation(e.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	legacy := e.Day != "" || e.Hour != "" || e.Minute != ""
+	count := 0
+	for _, set := range []bool{legacy, e.Cron != "", e.Every != "", e.At != ""} {
+		if set {
+			count++
+		}
+	}
+	if count != 1 {
+		return nil, fmt.Errorf("entry requires exactly one of day/hour/minute, cron, every or at")
+	}
+	switch {
+	case e.Every != "":
+		d, err := time.ParseDuration(e.Every)
+		if err != nil {
+			return nil, err
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("interval %v is shorter than a second", d)
+		}
+		return intervalSchedule{start: start.Truncate(time.Second), interval: d.Truncate(time.Second)}, nil
+	case e.At != "":
+		for _, layout := range atLayouts {
+			if t, err := time.ParseInLocation(layout, e.At, loc); err == nil {
+				return onceSchedule(t), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid time %q, expected format like 2006-01-02 15:04", e.At)
+	case e.Cron != "":
+		sched, err := parseCron(e.Cron)
+		if err != nil {
+			return nil, err
+		}
+		return zonedSchedule{schedule: sched, location: loc}, nil
+	default:
+		sched, err := cron.Parse(e.legacySpec())
+		if err != nil {
+			return nil, err
+		}
+		return zonedSchedule{schedule: sched, location: loc}, nil
+	}
+}
+
+//when describes the time of the entry
+func (e SchedulerEntity) when() string {
+	var when string
+	switch {
+	case e.Every != "":
+		when = "every " + e.Every
+	case e.At != "":
+		when = "once at " + e.At
+	case e.Cron != "":
+		when = "at " + e.Cron
+	default:
+		when = "at " + e.legacySpec()
+	}
+	if e.Timezone != "" {
+		when += " (" + e.Timezone + ")"
+	}
+	return when
+}
+
+//legacySpec returns the cron expression of day, hour and minute
+//A missing day or hour matches every value, a missing minute is the full hour
+func (e SchedulerEntity) legacySpec() string {
+	for _, field := range []*string{&e.Day, &e.Hour} {
+		if *field == "" {
+			*field = "*"
+		}
+	}
+	if e.Minute == "" {
+		e.Minute = "0"
+	}
+	return fmt.Sprintf("0 %s %s * * %s", e.Minute, e.Hour, e.Day)
+}
+
+//ParseCron parses a cron expression with seconds or a descriptor like @daily evaluated in timezone
+//like the cron field of schedule entries, an empty timezone is the local time of the host
+func ParseCron(spec, timezone string) (cron.Schedule, error) {
+	loc, err := loadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	sched, err := parseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	return zonedSchedule{schedule: sched, location: loc}, nil
+}
+
+//parseCron parses a cron expression with seconds or a descriptor like @daily
+//Five field crontab lines are rejected, the cron parser would take them as seconds to day-of-month
+func parseCron(spec string) (cron.Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if !strings.HasPrefix(spec, "@") && len(strings.Fields(spec)) != 6 {
+		return nil, fmt.Errorf("cron expression %q requires 6 fields: second minute hour day-of-month month day-of-week", spec)
+	}
+	return cron.Parse(spec)
+}
+
+//loadLocation returns the timezone with the given IANA name, local time if empty
+func loadLocation(name string) (*time.Location, error) {
+	if strings.TrimSpace(name) == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %v", name, err)
+	}
+	return loc, nil
+}
+
+//zonedSchedule evaluates the wrapped schedule in a fixed timezone
+type zonedSchedule struct {
+	schedule cron.Schedule
+	location *time.Location
+}
+
+//Next returns the next activation time later than t
+func (z zonedSchedule) Next(t time.Time) time.Time {
+	return z.schedule.Next(t.In(z.location))
+}
+
+//intervalSchedule activates every interval counted from a fixed start
+//Unlike cron.Every its activations do not depend on the time asked for, so offsets before them work
+type intervalSchedule struct {
+	start    time.Time
+	interval time.Duration
+}
+
+//Next returns the next activation time later than t
+func (i intervalSchedule) Next(t time.Time) time.Time {
+	if t.Before(i.start) {
+		return i.start.Add(i.interval)
+	}
+	n := t.Sub(i.start)/i.interval + 1
+	return i.start.Add(n * i.interval)
+}
+
+//onceSchedule activates a single time
+type onceSchedule time.Time
+
+//Next returns the activation time if it is later than t, zero time otherwise
+func (o onceSchedule) Next(t time.Time) time.Time {
+	if at := time.Time(o); at.After(t) {
+		return at
+	}
+	return time.Time{}
+}
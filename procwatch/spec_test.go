@@ -0,0 +1,105 @@
+package procwatch
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_EntitySchedule(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("Timezone database not available:", err)
+	}
+	now := time.Date(2017, 5, 1, 9, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		entry    SchedulerEntity
+		expected time.Time
+	}{
+		{SchedulerEntity{Day: "*", Hour: "12", Minute: "8"}, time.Date(2017, 5, 1, 12, 8, 0, 0, time.UTC)},
+		{SchedulerEntity{Hour: "12"}, time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{SchedulerEntity{Cron: "30 15 10 1 6 *"}, time.Date(2017, 6, 1, 10, 15, 30, 0, time.UTC)},
+		{SchedulerEntity{Cron: "@daily"}, time.Date(2017, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{SchedulerEntity{Every: "45m"}, now.Add(time.Minute * 45)},
+		{SchedulerEntity{At: "2017-05-01 20:00"}, time.Date(2017, 5, 1, 20, 0, 0, 0, time.UTC)},
+		{SchedulerEntity{At: "2017-04-01 20:00"}, time.Time{}},
+		{SchedulerEntity{Hour: "12", Minute: "0", Timezone: "Europe/Berlin"}, time.Date(2017, 5, 1, 12, 0, 0, 0, berlin)},
+	}
+	for _, v := range tests {
+		sched, err := v.entry.schedule(time.UTC, now)
+		if err != nil {
+			t.Errorf("%v: %v", v.entry, err)
+			continue
+		}
+		if res := sched.Next(now); !res.Equal(v.expected) {
+			t.Errorf("%v Expected: %v Got: %v", v.entry, v.expected, res)
+		}
+	}
+
+	sched, _ := SchedulerEntity{Hour: "12"}.schedule(time.UTC, now)
+	first := sched.Next(now)
+	if second := sched.Next(first); !second.Equal(first.Add(time.Hour * 24)) {
+		t.Error("Expected an Entry with only hour to fire once a day Got:", first, second)
+	}
+}
+
+func Test_EntityScheduleInvalid(t *testing.T) {
+	var tests = []SchedulerEntity{
+		{},
+		{Hour: "12", Every: "1h"},
+		{Every: "soon"},
+		{Every: "10ms"},
+		{At: "tomorrow"},
+		{Cron: "* * *"},
+		{Cron: "0 6 * * *"},
+		{Cron: "@sometimes"},
+		{Hour: "12", Timezone: "Mars/Olympus"},
+	}
+	for _, entry := range tests {
+		if _, err := entry.schedule(time.UTC, time.Now()); err == nil {
+			t.Errorf("%+v: Expected Error", entry)
+		}
+	}
+}
+
+func Test_Preview(t *testing.T) {
+	zero := 0
+	schedule := Schedule{Schedule: []SchedulerEntity{
+		{Restart: true, Hour: "6", Minute: "0", Warnings: []int{30, 5}, Conditions: Conditions{MaxPlayers: &zero}},
+		{Command: "say -1 Hello", At: "2017-05-01 20:00"},
+		{Command: "say -1 Broken", Hour: "25"},
+		{Name: "backup", Command: "#lock", Every: "1h"},
+		{Name: "backup", Command: "#unlock", Every: "1h"},
+		{Every: "24h", Task: Task{Action: "stop"}},
+	}}
+	now := time.Date(2017, 5, 1, 9, 0, 0, 0, time.UTC)
+	previews, err := schedule.Preview("UTC", false, 2, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restart := previews[0]
+	if restart.Action != "restart with warnings 30, 5 minutes before" || restart.Conditions != "maxPlayers 0" || restart.Err != nil {
+		t.Errorf("Unexpected Preview: %+v", restart)
+	}
+	expected := []time.Time{time.Date(2017, 5, 2, 6, 0, 0, 0, time.UTC), time.Date(2017, 5, 3, 6, 0, 0, 0, time.UTC)}
+	if len(restart.Next) != 2 || !restart.Next[0].Equal(expected[0]) || !restart.Next[1].Equal(expected[1]) {
+		t.Error("Expected:", expected, "Got:", restart.Next)
+	}
+	if len(previews[1].Next) != 1 || previews[1].Action != `send "say -1 Hello"` {
+		t.Errorf("Unexpected One-Shot Preview: %+v", previews[1])
+	}
+	if previews[2].Err == nil {
+		t.Error("Expected Error for invalid Entry")
+	}
+	if previews[3].Err != nil || previews[4].Err == nil {
+		t.Error("Expected Error for duplicate Name Got:", previews[3].Err, previews[4].Err)
+	}
+	if previews[5].Err == nil {
+		t.Error("Expected Error for stop Action without Watcher")
+	}
+	if previews, _ := schedule.Preview("UTC", true, 2, now); previews[5].Err != nil {
+		t.Error("Expected stop Action to be valid with Watcher Got:", previews[5].Err)
+	}
+	if _, err := schedule.Preview("Mars/Olympus", false, 1, now); err == nil {
+		t.Error("Expected Error for invalid Timezone")
+	}
+}
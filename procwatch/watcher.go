@@ -1,14 +1,25 @@
 package procwatch
 
 import (
+	"errors"
 	"io"
 	"os/exec"
 	"path"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
-	"github.com/robfig/cron"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+var (
+	//ErrRestarting is returned when restarting or stopping the server while a restart is in progress
+	ErrRestarting = errors.New("Restart in progress")
+	//ErrStopped is returned when restarting or stopping a server which is not running
+	ErrStopped = errors.New("Server is not running")
+	//ErrRunning is returned when starting a server which is running
+	ErrRunning = errors.New("Server is already running")
 )
 
 //Cfg contains all data required by Procwatch
@@ -18,7 +29,13 @@ type Cfg struct {
 	Schedule     Schedule
 	UseScheduler bool
 	UseWatcher   bool
-	//Timezone int
+	Shutdown     Shutdown
+	//Timezone is the IANA name of the default timezone of the schedule, empty for local time
+	Timezone string
+	//StatePath is the file the last fire times of the jobs are persisted in, empty to keep them in memory
+	StatePath string
+	//CatchUpDelay is the time after start missed jobs are caught up, giving RCon time to connect
+	CatchUpDelay time.Duration
 }
 
 //Config is the Interface providing Configs for the Procwatch
@@ -35,70 +52,122 @@ func (c Cfg) GetConfig() Cfg {
 type Watcher struct {
 	a3exe        string
 	a3par        []string
-	pid          uint32
 	waitGroup    sync.WaitGroup
-	cmd          *exec.Cmd
 	schedule     Schedule
-	cron         cron.Cron
+	jobs         *jobs
+	jobsLock     sync.RWMutex
 	cmdChan      chan string
-	stdout       io.ReadCloser
-	stderr       io.ReadCloser
 	useWatcher   bool
 	useScheduler bool
+	shutdown     Shutdown
+	timezone     string
+	created      time.Time
+	emptySince   time.Time
+	emptyLock    sync.Mutex
+	querier      players.Querier
+	restarting   int32
+	paused       int32
+	stopped      int32
+	state        *stateStore
+	catchUpDelay time.Duration
+
+	//proc is the watched process, replaced on every start
+	proc struct {
+		sync.Mutex
+		cmd     *exec.Cmd
+		pid     uint32
+		started time.Time
+		exited  chan struct{}
+		stdout  io.ReadCloser
+		stderr  io.ReadCloser
+	}
 }
 
 //New creates a Procwatch with given Config
 func New(w Config) *Watcher {
 	cfg := w.GetConfig()
+	state, err := loadState(cfg.StatePath)
+	if err != nil {
+		glog.Errorln("Could not load Schedule State, missed jobs are not caught up:", err)
+	}
 
 	return &Watcher{
 		a3exe:        cfg.A3exe,
 		a3par:        cfg.A3par,
 		schedule:     cfg.Schedule,
-		cron:         *cron.New(),
 		cmdChan:      make(chan string),
 		useScheduler: cfg.UseScheduler,
 		useWatcher:   cfg.UseWatcher,
+		shutdown:     cfg.Shutdown.withDefaults(),
+		timezone:     cfg.Timezone,
+		created:      time.Now(),
+		state:        state,
+		catchUpDelay: cfg.CatchUpDelay,
 	}
 }
 
 //Start the Server
 func (w *Watcher) Start() {
-	var err error
 	if w.useWatcher {
 		glog.V(2).Infoln("Starting Watcher")
-		w.cmd = exec.Command(w.a3exe, w.a3par...)
-		w.cmd.Dir = path.Dir(w.a3exe)
-		glog.V(2).Infof("Executing ArmA Executable: %v", w.cmd)
-		w.stdout, err = w.cmd.StdoutPipe()
-		if err != nil {
-			glog.Error(err)
-		}
-		w.stderr, err = w.cmd.StderrPipe()
-		if err != nil {
-			glog.Error(err)
-		}
-		err = w.cmd.Start()
-		if err == nil {
-			w.pid = uint32(w.cmd.Process.Pid)
-			w.waitGroup = sync.WaitGroup{}
-			w.waitGroup.Add(1)
-			go w.wait()
-		} else {
-			glog.Fatalln(err)
-			return
-		}
+		w.startProcess()
 	}
 
 	if w.useScheduler {
 		glog.V(2).Infoln("Starting Scheduler")
-		err = w.buildJobs()
+		err := w.buildJobs()
 		if err != nil {
 			glog.Error(err)
+			return
 		}
+		go w.catchUp(w.catchUpDelay)
 	}
 }
 
+//startProcess executes the server and watches it until it exits
+func (w *Watcher) startProcess() {
+	cmd := exec.Command(w.a3exe, w.a3par...)
+	cmd.Dir = path.Dir(w.a3exe)
+	glog.V(2).Infof("Executing ArmA Executable: %v", cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		glog.Error(err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		glog.Error(err)
+	}
+	err = cmd.Start()
+	if err != nil {
+		glog.Fatalln(err)
+		return
+	}
+	exited := make(chan struct{})
+	w.proc.Lock()
+	w.proc.cmd, w.proc.exited = cmd, exited
+	w.proc.pid = uint32(cmd.Process.Pid)
+	w.proc.started = time.Now()
+	w.proc.stdout, w.proc.stderr = stdout, stderr
+	w.proc.Unlock()
+	w.waitGroup = sync.WaitGroup{}
+	w.waitGroup.Add(1)
+	go w.wait(cmd, exited)
+}
+
+//process returns the watched process and the channel closed once it exited, nil if it was never started
+func (w *Watcher) process() (*exec.Cmd, chan struct{}) {
+	w.proc.Lock()
+	defer w.proc.Unlock()
+	return w.proc.cmd, w.proc.exited
+}
+
+//exitedProcess marks the watched process as not running
+func (w *Watcher) exitedProcess() {
+	w.proc.Lock()
+	w.proc.pid = 0
+	w.proc.Unlock()
+}
+
 //GetCmdChannel returns the channel to which scheduler and watcher write their commands
 func (w *Watcher) GetCmdChannel() chan string {
 	if w.cmdChan != nil {
@@ -109,34 +178,109 @@ func (w *Watcher) GetCmdChannel() chan string {
 
 //GetOutput returns the Stderr and Stdout Readers
 func (w *Watcher) GetOutput() (stderr, stdout io.ReadCloser) {
-	stderr = w.stderr
+	w.proc.Lock()
+	defer w.proc.Unlock()
+	stderr = w.proc.stderr
 	if stderr == nil {
 		panic("No Stderr")
 	}
-	stdout = w.stdout
+	stdout = w.proc.stdout
 	if stderr == nil {
 		panic("No Stdout")
 	}
 	return
 }
 
+//Started returns when the watched process was started, zero if it is not running
+func (w *Watcher) Started() time.Time {
+	w.proc.Lock()
+	defer w.proc.Unlock()
+	if w.proc.pid == 0 {
+		return time.Time{}
+	}
+	return w.proc.started
+}
+
 //Wait for Server to exit
-func (w *Watcher) wait() {
+func (w *Watcher) wait(cmd *exec.Cmd, exited chan struct{}) {
 	defer w.waitGroup.Done()
 
-	procwait, err := w.cmd.Process.Wait()
+	procwait, err := cmd.Process.Wait()
+	//a stopped server is settled before exited is closed so StartServer can follow StopServer immediately
+	if atomic.LoadInt32(&w.stopped) == 1 {
+		w.exitedProcess()
+		atomic.StoreInt32(&w.restarting, 0)
+		glog.Infoln("Stop: server stopped")
+		close(exited)
+		return
+	}
+	close(exited)
 	if err != nil {
 		return
 	}
 
-	if procwait.Exited() {
+	if procwait.Exited() || atomic.LoadInt32(&w.restarting) == 1 {
 		w.restart()
 	}
 }
 
+//Restart the Server
+//If the process is watched it is shut down by the restart pipeline and started again once it exited,
+//otherwise #restartserver is sent over RCon
+func (w *Watcher) Restart() error {
+	if w.useWatcher && atomic.LoadInt32(&w.stopped) == 1 {
+		return ErrStopped
+	}
+	if !atomic.CompareAndSwapInt32(&w.restarting, 0, 1) {
+		return ErrRestarting
+	}
+	if w.useWatcher {
+		return w.shutdownProcess()
+	}
+	defer atomic.StoreInt32(&w.restarting, 0)
+	w.prepareShutdown()
+	glog.V(2).Infoln("Sending Restart Command to Channel")
+	if !w.send("#restartserver") {
+		return errors.New("#restartserver was not sent")
+	}
+	return nil
+}
+
 //Restart the Server
 func (w *Watcher) restart() {
 	time.Sleep(time.Second * 5)
-	w.pid = 0
-	w.Start()
+	w.exitedProcess()
+	atomic.StoreInt32(&w.restarting, 0)
+	glog.Infoln("Restart: starting server")
+	w.startProcess()
+}
+
+//StopServer shuts the watched process down like a restart without starting it again
+func (w *Watcher) StopServer() error {
+	if atomic.LoadInt32(&w.stopped) == 1 {
+		return ErrStopped
+	}
+	if !atomic.CompareAndSwapInt32(&w.restarting, 0, 1) {
+		return ErrRestarting
+	}
+	atomic.StoreInt32(&w.stopped, 1)
+	_, exited := w.process()
+	if err := w.shutdownProcess(); err != nil {
+		return err
+	}
+	if !w.waitExit(exited, w.shutdown.KillTimeout) {
+		atomic.StoreInt32(&w.restarting, 0)
+		return errors.New("server did not exit")
+	}
+	return nil
+}
+
+//StartServer starts the watched process after it was stopped by StopServer
+func (w *Watcher) StartServer() error {
+	if !atomic.CompareAndSwapInt32(&w.stopped, 1, 0) {
+		return ErrRunning
+	}
+	glog.Infoln("Start: starting server")
+	w.startProcess()
+	return nil
 }
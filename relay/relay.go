@@ -0,0 +1,190 @@
+package relay
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Client is the RCon Connection used to broadcast relayed messages
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Server taking part in the relay
+type Server struct {
+	Tag    string
+	Client Client
+	Roster *players.Roster
+	//Online reports whether the server can be reached, messages to unreachable servers are dropped
+	//A nil Online counts as always reachable
+	Online func() bool
+}
+
+//Cfg contains all data required by the Relay
+type Cfg struct {
+	Channels []string
+	Format   string
+	Command  string
+}
+
+//Config is the Interface providing Configs for the Relay
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//echoTimeout is how long relayed messages are remembered to drop them if they come back
+var echoTimeout = time.Second * 30
+
+//Relay re-broadcasts chat messages between servers
+type Relay struct {
+	servers  []*Server
+	db       *playerdb.DB
+	channels map[string]bool
+	format   string
+	command  string
+
+	sent struct {
+		sync.Mutex
+		m map[string]time.Time
+	}
+}
+
+//New creates a Relay between servers with given Config
+func New(c Config, db *playerdb.DB, servers ...*Server) (*Relay, error) {
+	cfg := c.GetConfig()
+	if len(servers) < 2 {
+		return nil, fmt.Errorf("chat relay requires at least 2 servers, got %d", len(servers))
+	}
+	if len(cfg.Channels) == 0 {
+		cfg.Channels = []string{"Global"}
+	}
+	if cfg.Format == "" {
+		cfg.Format = "[{tag}] {name}: {text}"
+	}
+	if cfg.Command == "" {
+		cfg.Command = "!relay"
+	}
+	r := &Relay{
+		servers:  servers,
+		db:       db,
+		channels: make(map[string]bool),
+		format:   cfg.Format,
+		command:  cfg.Command,
+	}
+	r.sent.m = make(map[string]time.Time)
+	for _, channel := range cfg.Channels {
+		r.channels[strings.ToLower(channel)] = true
+	}
+	return r, nil
+}
+
+//Handler returns the chat handler for messages from server
+func (r *Relay) Handler(from *Server) func(events.Chat) {
+	return func(e events.Chat) {
+		r.handleChat(from, e)
+	}
+}
+
+func (r *Relay) handleChat(from *Server, e events.Chat) {
+	p, text, err := from.Roster.Sender(e.Message)
+	if err != nil {
+		glog.V(3).Infof("Relay could not attribute %q: %v", e.Message, err)
+		return
+	}
+	text = strings.TrimSpace(text)
+	if fields := strings.Fields(text); len(fields) > 0 && strings.EqualFold(fields[0], r.command) {
+		r.optOut(from, p, fields[1:])
+		return
+	}
+	if !r.channels[strings.ToLower(e.Channel)] || strings.HasPrefix(text, "!") || r.echo(p.Name, text) {
+		return
+	}
+	if p.GUID == "" || r.db.Get(p.GUID).RelayOptOut {
+		return
+	}
+	line := message.Render(r.format, message.Vars{
+		"tag":     from.Tag,
+		"name":    p.Name,
+		"text":    text,
+		"channel": e.Channel,
+	})
+	r.remember(line)
+	for _, to := range r.servers {
+		if to == from {
+			continue
+		}
+		if to.Online != nil && !to.Online() {
+			glog.V(3).Infof("Relay skipping unreachable %v: %v", to.Tag, line)
+			continue
+		}
+		glog.V(3).Infof("Relaying from %v to %v: %v", from.Tag, to.Tag, line)
+		to.Client.RunCommand("say -1 "+line, nil)
+	}
+}
+
+//echo returns whether the message is one the relay sent itself
+func (r *Relay) echo(name, text string) bool {
+	for _, s := range r.servers {
+		if strings.HasPrefix(text, "["+s.Tag+"]") {
+			return true
+		}
+	}
+	r.sent.Lock()
+	defer r.sent.Unlock()
+	now := time.Now()
+	for line, at := range r.sent.m {
+		if now.Sub(at) > echoTimeout {
+			delete(r.sent.m, line)
+		}
+	}
+	_, ok := r.sent.m[text]
+	if !ok {
+		_, ok = r.sent.m[name+": "+text]
+	}
+	return ok
+}
+
+func (r *Relay) remember(line string) {
+	r.sent.Lock()
+	r.sent.m[line] = time.Now()
+	r.sent.Unlock()
+}
+
+//optOut handles the relay command to stop or resume relaying the messages of a player
+func (r *Relay) optOut(from *Server, p players.Player, args []string) {
+	if p.GUID == "" {
+		return
+	}
+	optOut := !r.db.Get(p.GUID).RelayOptOut
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "off":
+			optOut = true
+		case "on":
+			optOut = false
+		}
+	}
+	if _, err := r.db.Update(p.GUID, func(rec *playerdb.Record) { rec.RelayOptOut = optOut }); err != nil {
+		glog.Errorln("Could not store Relay Opt-Out:", err)
+		return
+	}
+	answer := "Your messages are relayed to the other servers again"
+	if optOut {
+		answer = "Your messages are no longer relayed to the other servers"
+	}
+	from.Client.RunCommand(fmt.Sprintf("say %d %s", p.Number, answer), nil)
+}
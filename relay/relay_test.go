@@ -0,0 +1,103 @@
+package relay
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+func newServer(tag string) (*Server, *fakeClient) {
+	client := &fakeClient{}
+	roster := players.NewRoster()
+	roster.Add(players.Player{Number: 2, Name: "Steve"})
+	roster.SetGUID(2, tag+"0000000000000000000000000000000")
+	return &Server{Tag: tag, Client: client, Roster: roster}, client
+}
+
+func Test_Relay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := playerdb.Open(path.Join(dir, "players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, clientA := newServer("A")
+	b, clientB := newServer("B")
+	r, err := New(Cfg{}, db, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromA, fromB := r.Handler(a), r.Handler(b)
+
+	fromA(events.Chat{Channel: "Global", Name: "Steve", Text: "hello", Message: "Steve: hello"})
+	fromA(events.Chat{Channel: "Side", Name: "Steve", Text: "side chat", Message: "Steve: side chat"})
+	fromA(events.Chat{Channel: "Global", Name: "Steve", Text: "!help", Message: "Steve: !help"})
+	fromA(events.Chat{Channel: "Global", Name: "Unknown", Text: "hello", Message: "Unknown: hello"})
+	expected := []string{"say -1 [A] Steve: hello"}
+	if !reflect.DeepEqual(clientB.commands, expected) {
+		t.Error("Expected:", expected, "Got:", clientB.commands)
+	}
+
+	fromB(events.Chat{Channel: "Global", Name: "BattlEye Server", Text: "[A] Steve: hello", Message: "BattlEye Server: [A] Steve: hello"})
+	fromB(events.Chat{Channel: "Global", Name: "Steve", Text: "[A] Steve: hello", Message: "Steve: [A] Steve: hello"})
+	if len(clientA.commands) != 0 {
+		t.Error("Expected relayed Messages to be dropped Got:", clientA.commands)
+	}
+
+	fromA(events.Chat{Channel: "Global", Name: "Steve", Text: "!relay off", Message: "Steve: !relay off"})
+	fromA(events.Chat{Channel: "Global", Name: "Steve", Text: "private", Message: "Steve: private"})
+	expected = []string{"say 2 Your messages are no longer relayed to the other servers"}
+	if !reflect.DeepEqual(clientA.commands, expected) || len(clientB.commands) != 1 {
+		t.Error("Expected Opt-Out Got:", clientA.commands, clientB.commands)
+	}
+	if !db.Get("A0000000000000000000000000000000").RelayOptOut {
+		t.Error("Expected Opt-Out to be persisted")
+	}
+}
+
+func Test_RelayOffline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := playerdb.Open(path.Join(dir, "players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := newServer("A")
+	b, clientB := newServer("B")
+	c, clientC := newServer("C")
+	b.Online = func() bool { return false }
+	r, err := New(Cfg{}, db, a, b, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Handler(a)(events.Chat{Channel: "Global", Name: "Steve", Text: "hello", Message: "Steve: hello"})
+	if len(clientB.commands) != 0 {
+		t.Error("Expected unreachable Server to be skipped Got:", clientB.commands)
+	}
+	expected := []string{"say -1 [A] Steve: hello"}
+	if !reflect.DeepEqual(clientC.commands, expected) {
+		t.Error("Expected:", expected, "Got:", clientC.commands)
+	}
+}
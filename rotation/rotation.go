@@ -0,0 +1,317 @@
+package rotation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/players"
+	"github.com/playnet-public/gorcon-arma/procwatch"
+	"github.com/robfig/cron"
+)
+
+//ErrNoMissions is returned when none of the configured missions is available
+var ErrNoMissions = errors.New("Rotation has no missions")
+
+//Mission is an entry of the rotation
+type Mission struct {
+	Name       string
+	Difficulty string
+	//Weight is the relative chance of the mission in weighted rotations, 0 counts as 1
+	Weight int
+}
+
+//String returns the mission name with its difficulty
+func (m Mission) String() string {
+	if m.Difficulty == "" {
+		return m.Name
+	}
+	return fmt.Sprintf("%s (%s)", m.Name, m.Difficulty)
+}
+
+//Cfg contains all data required by the Rotation
+type Cfg struct {
+	Missions []Mission
+	Weighted bool
+	//Switch are cron expressions with seconds at which the next mission is started
+	Switch []string
+	//Timezone the switches are evaluated in, empty for the local time of the host
+	Timezone string
+	//OnEnd starts the next mission once all players are back in the lobby
+	OnEnd bool
+	Check time.Duration
+	//Grace is the time after a switch during which players in the lobby are expected
+	Grace time.Duration
+	//Warnings are the minutes before a scheduled switch the upcoming mission is announced
+	Warnings    []int
+	Message     string
+	NextMessage string
+}
+
+//Config is the Interface providing Configs for the Rotation
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to list and switch missions
+type Client interface {
+	players.Querier
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Rotation switches the missions of the server in order or by weight
+type Rotation struct {
+	client      Client
+	missions    []Mission
+	weighted    bool
+	schedules   []cron.Schedule
+	onEnd       bool
+	check       time.Duration
+	grace       time.Duration
+	warnings    []int
+	message     string
+	nextMessage string
+
+	sync.Mutex
+	current       int
+	upcoming      int
+	switched      time.Time
+	ended         int
+	announcedNext bool
+}
+
+//New creates a Rotation with given Config
+func New(c Config, client Client) (*Rotation, error) {
+	cfg := c.GetConfig()
+	if len(cfg.Missions) == 0 {
+		return nil, ErrNoMissions
+	}
+	if cfg.Check == 0 {
+		cfg.Check = time.Second * 30
+	}
+	if cfg.Grace == 0 {
+		cfg.Grace = time.Minute * 5
+	}
+	if cfg.Message == "" {
+		cfg.Message = "Next mission {mission} starts in {minutes} minutes"
+	}
+	if cfg.NextMessage == "" {
+		cfg.NextMessage = "Next mission: {mission}"
+	}
+	r := &Rotation{
+		client:      client,
+		weighted:    cfg.Weighted,
+		onEnd:       cfg.OnEnd,
+		check:       cfg.Check,
+		grace:       cfg.Grace,
+		warnings:    cfg.Warnings,
+		message:     cfg.Message,
+		nextMessage: cfg.NextMessage,
+		current:     -1,
+		switched:    time.Now(),
+	}
+	for _, m := range cfg.Missions {
+		m.Name = strings.TrimSuffix(strings.TrimSpace(m.Name), ".pbo")
+		if m.Name == "" {
+			return nil, errors.New("Rotation mission without name")
+		}
+		if m.Weight < 0 {
+			return nil, fmt.Errorf("Rotation mission %v has negative weight", m.Name)
+		}
+		if m.Weight == 0 {
+			m.Weight = 1
+		}
+		r.missions = append(r.missions, m)
+	}
+	for _, spec := range cfg.Switch {
+		sched, err := procwatch.ParseCron(spec, cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("Rotation switch %q: %v", spec, err)
+		}
+		r.schedules = append(r.schedules, sched)
+	}
+	r.upcoming = r.pick()
+	return r, nil
+}
+
+//Validate drops the missions which are not listed by the missions command of the server
+func (r *Rotation) Validate() error {
+	res, err := r.client.Query("missions", players.QueryTimeout)
+	if err != nil {
+		return err
+	}
+	available := make(map[string]bool)
+	for _, name := range parseMissions(res) {
+		available[strings.ToLower(name)] = true
+	}
+	r.Lock()
+	defer r.Unlock()
+	var missions, unknown []Mission
+	for _, m := range r.missions {
+		if available[strings.ToLower(m.Name)] {
+			missions = append(missions, m)
+		} else {
+			unknown = append(unknown, m)
+		}
+	}
+	if len(missions) == 0 {
+		return ErrNoMissions
+	}
+	r.missions = missions
+	r.current = -1
+	r.upcoming = r.pick()
+	if len(unknown) > 0 {
+		return fmt.Errorf("Rotation dropped missions unknown to the server: %v", unknown)
+	}
+	return nil
+}
+
+//parseMissions returns the mission names of the missions command output
+func parseMissions(list string) []string {
+	var names []string
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasSuffix(line, ":") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(line, ".pbo"))
+	}
+	return names
+}
+
+//pick returns the index of the mission following the current one
+//Weighted rotations pick randomly by weight and never repeat the current mission
+func (r *Rotation) pick() int {
+	if !r.weighted {
+		return (r.current + 1) % len(r.missions)
+	}
+	total := 0
+	for i, m := range r.missions {
+		if i != r.current || len(r.missions) == 1 {
+			total += m.Weight
+		}
+	}
+	n := rand.Intn(total)
+	for i, m := range r.missions {
+		if i == r.current && len(r.missions) > 1 {
+			continue
+		}
+		if n < m.Weight {
+			return i
+		}
+		n -= m.Weight
+	}
+	return 0
+}
+
+//Upcoming returns the mission started by the next switch
+func (r *Rotation) Upcoming() Mission {
+	r.Lock()
+	defer r.Unlock()
+	return r.missions[r.upcoming]
+}
+
+//Advance starts the upcoming mission and picks the one after it
+func (r *Rotation) Advance() {
+	r.Lock()
+	defer r.Unlock()
+	m := r.missions[r.upcoming]
+	glog.Infoln("Rotation: starting mission", m)
+	cmd := "#mission " + m.Name
+	if m.Difficulty != "" {
+		cmd += " " + m.Difficulty
+	}
+	r.client.RunCommand(cmd, nil)
+	r.current = r.upcoming
+	r.upcoming = r.pick()
+	r.switched = time.Now()
+	r.ended = 0
+	r.announcedNext = false
+}
+
+//announce broadcasts the upcoming mission with the given template
+func (r *Rotation) announce(tpl string, minutes int) {
+	m := r.Upcoming()
+	text := message.Render(tpl, message.Vars{
+		"mission":    m.Name,
+		"difficulty": m.Difficulty,
+		"minutes":    minutes,
+	})
+	glog.V(2).Infoln("Rotation: announcing", text)
+	r.client.RunCommand("say -1 "+text, nil)
+}
+
+//Run schedules the switches with their announcements and watches for mission ends
+func (r *Rotation) Run() {
+	c := cron.New()
+	for _, sched := range r.schedules {
+		c.Schedule(sched, cron.FuncJob(r.Advance))
+		for _, minutes := range r.warnings {
+			if minutes <= 0 {
+				continue
+			}
+			minutes := minutes
+			c.Schedule(procwatch.OffsetSchedule{Schedule: sched, Offset: time.Minute * time.Duration(minutes)}, cron.FuncJob(func() {
+				r.announce(r.message, minutes)
+			}))
+		}
+	}
+	c.Start()
+	if !r.onEnd {
+		return
+	}
+	for {
+		glog.V(10).Infoln("Looping in Rotation")
+		time.Sleep(r.check)
+		r.poll()
+	}
+}
+
+//poll advances the rotation once all players returned to the lobby on two consecutive checks
+//The upcoming mission is announced once the grace period after a switch passed
+func (r *Rotation) poll() {
+	list, err := players.List(r.client)
+	if err != nil {
+		glog.V(2).Infoln("Rotation: could not query Players:", err)
+		return
+	}
+	r.Lock()
+	inGrace := time.Since(r.switched) < r.grace
+	announce := !inGrace && !r.announcedNext
+	if announce {
+		r.announcedNext = true
+	}
+	lobby := len(list) > 0
+	for _, p := range list {
+		lobby = lobby && p.Lobby
+	}
+	if inGrace || !lobby {
+		r.ended = 0
+	} else {
+		r.ended++
+	}
+	ended := r.ended >= 2
+	r.Unlock()
+
+	if ended {
+		glog.Infoln("Rotation: mission ended")
+		r.Advance()
+		return
+	}
+	if announce {
+		r.announce(r.nextMessage, 0)
+	}
+}
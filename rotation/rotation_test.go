@@ -0,0 +1,116 @@
+package rotation
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeClient struct {
+	responses map[string]string
+	commands  []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+func (f *fakeClient) Query(cmd string, timeout time.Duration) (string, error) {
+	return f.responses[cmd], nil
+}
+
+func Test_Validate(t *testing.T) {
+	client := &fakeClient{responses: map[string]string{
+		"missions": "Missions on server:\nco10_Escape.Altis\nKOTH.Tanoa.pbo\n",
+	}}
+	r, err := New(Cfg{Missions: []Mission{{Name: "co10_escape.Altis"}, {Name: "Missing.Stratis"}, {Name: "KOTH.Tanoa"}}}, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Validate(); err == nil {
+		t.Error("Expected Error for unknown Mission")
+	}
+	if len(r.missions) != 2 {
+		t.Error("Expected unknown Mission to be dropped Got:", r.missions)
+	}
+
+	client.responses["missions"] = "Missions on server:\n"
+	if err := r.Validate(); err != ErrNoMissions {
+		t.Error("Expected ErrNoMissions Got:", err)
+	}
+}
+
+func Test_Advance(t *testing.T) {
+	client := &fakeClient{}
+	r, err := New(Cfg{Missions: []Mission{{Name: "a.Altis", Difficulty: "Veteran"}, {Name: "b.Tanoa"}}}, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.announce(r.message, 5)
+	r.Advance()
+	r.Advance()
+	r.Advance()
+	expected := []string{
+		"say -1 Next mission a.Altis starts in 5 minutes",
+		"#mission a.Altis Veteran",
+		"#mission b.Tanoa",
+		"#mission a.Altis Veteran",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_Switch(t *testing.T) {
+	missions := []Mission{{Name: "a"}}
+	if _, err := New(Cfg{Missions: missions, Switch: []string{"0 0 */4 * * *", "@daily"}, Timezone: "Europe/Berlin"}, &fakeClient{}); err != nil {
+		t.Error(err)
+	}
+	if _, err := New(Cfg{Missions: missions, Switch: []string{"0 6 * * *"}}, &fakeClient{}); err == nil {
+		t.Error("Expected Error for Switch without seconds")
+	}
+	if _, err := New(Cfg{Missions: missions, Switch: []string{"@daily"}, Timezone: "Mars/Olympus"}, &fakeClient{}); err == nil {
+		t.Error("Expected Error for invalid Timezone")
+	}
+}
+
+func Test_Weighted(t *testing.T) {
+	r, err := New(Cfg{Weighted: true, Missions: []Mission{{Name: "a"}, {Name: "b", Weight: 3}, {Name: "c", Weight: 0}}}, &fakeClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[int]int)
+	r.current = 0
+	for i := 0; i < 1000; i++ {
+		counts[r.pick()]++
+	}
+	if counts[0] != 0 {
+		t.Error("Expected current Mission not to repeat Got:", counts[0])
+	}
+	if counts[1] < counts[2]*2 {
+		t.Error("Expected weighted picks Got:", counts)
+	}
+}
+
+func Test_Poll(t *testing.T) {
+	client := &fakeClient{responses: map[string]string{
+		"players": "0   127.0.0.1:2304   31   0123456789abcdef0123456789abcdef(OK) Steve (Lobby)\n",
+	}}
+	r, err := New(Cfg{OnEnd: true, Grace: time.Hour, Missions: []Mission{{Name: "a"}, {Name: "b"}}}, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.poll()
+	r.poll()
+	if len(client.commands) != 0 {
+		t.Error("Expected no Switch within Grace Got:", client.commands)
+	}
+	r.grace = 0
+	r.poll()
+	r.poll()
+	expected := []string{"say -1 Next mission: a", "#mission a"}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
@@ -0,0 +1,133 @@
+package slots
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Member with reserved slot, members listed first have the highest priority
+type Member struct {
+	GUID string
+}
+
+//Cfg contains all data required by the Slots
+type Cfg struct {
+	Limit   int
+	Members []Member
+	Admins  []string
+	Message string
+	DryRun  bool
+}
+
+//Config is the Interface providing Configs for the Slots
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to list and kick players
+type Client interface {
+	players.Querier
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Slots frees a slot for members by kicking the player with the lowest priority once the server is full
+type Slots struct {
+	client   Client
+	roster   *players.Roster
+	limit    int
+	priority map[string]int
+	message  string
+	dryRun   bool
+}
+
+//New creates Slots with given Config, members are ranked by their order and admins always have the highest priority
+func New(c Config, client Client, roster *players.Roster) *Slots {
+	cfg := c.GetConfig()
+	if cfg.Message == "" {
+		cfg.Message = "Slot reserved for members"
+	}
+	s := &Slots{
+		client:   client,
+		roster:   roster,
+		limit:    cfg.Limit,
+		priority: make(map[string]int),
+		message:  cfg.Message,
+		dryRun:   cfg.DryRun,
+	}
+	for i, m := range cfg.Members {
+		guid := strings.ToLower(m.GUID)
+		if _, ok := s.priority[guid]; !ok {
+			s.priority[guid] = len(cfg.Members) - i
+		}
+	}
+	for _, guid := range cfg.Admins {
+		s.priority[strings.ToLower(guid)] = math.MaxInt32
+	}
+	return s
+}
+
+//HandleGUIDVerified kicks a player if a member joined a full server
+func (s *Slots) HandleGUIDVerified(e events.GUIDVerified) {
+	if s.priority[e.GUID] == 0 {
+		return
+	}
+	list, err := players.List(s.client)
+	if err != nil {
+		glog.Errorln("Slots could not retrieve Players:", err)
+		return
+	}
+	if len(list) <= s.limit {
+		return
+	}
+	victim, ok := s.victim(list, e.GUID)
+	if !ok {
+		glog.Warningf("Slots: %v joined the full server (%v/%v) but nobody can be kicked", e.Name, len(list), s.limit)
+		return
+	}
+	text := message.Render(s.message, message.Vars{"name": victim.Name, "member": e.Name})
+	if s.dryRun {
+		glog.Infof("Slots (dry-run): would kick %v for %v (%v/%v players)", victim.Name, e.Name, len(list), s.limit)
+		return
+	}
+	glog.Infof("Slots: kicking %v for %v (%v/%v players)", victim.Name, e.Name, len(list), s.limit)
+	s.client.RunCommand(fmt.Sprintf("kick %d %s", victim.Number, text), nil)
+}
+
+//victim returns the player with the lowest priority ranking below guid, public players have no priority
+//Players with the same priority are kicked starting with the most recently joined
+func (s *Slots) victim(list []players.Player, guid string) (players.Player, bool) {
+	var candidates []players.Player
+	for _, p := range list {
+		if p.GUID == guid || s.priority[p.GUID] >= s.priority[guid] {
+			continue
+		}
+		if known, ok := s.roster.ByNumber(p.Number); ok && known.Name == p.Name {
+			p.Joined = known.Joined
+		}
+		candidates = append(candidates, p)
+	}
+	if len(candidates) == 0 {
+		return players.Player{}, false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		pi, pj := s.priority[candidates[i].GUID], s.priority[candidates[j].GUID]
+		if pi != pj {
+			return pi < pj
+		}
+		return candidates[i].Joined.After(candidates[j].Joined)
+	})
+	return candidates[0], true
+}
@@ -0,0 +1,107 @@
+package slots
+
+import (
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+const listOutput = `Players on server:
+[#] [IP Address]:[Port] [Ping] [GUID] [Name]
+--------------------------------------------------
+0   127.0.0.1:2304   31   00000000000000000000000000000000(OK) Early
+1   127.0.0.1:2304   31   11111111111111111111111111111111(OK) Late
+2   127.0.0.1:2304   31   22222222222222222222222222222222(OK) Member
+3   127.0.0.1:2304   31   33333333333333333333333333333333(OK) NewMember
+(4 players in total)
+`
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) Query(cmd string, timeout time.Duration) (string, error) {
+	return listOutput, nil
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+func newSlots(dryRun bool) (*Slots, *fakeClient) {
+	roster := players.NewRoster()
+	now := time.Now()
+	roster.Add(players.Player{Number: 0, Name: "Early", Joined: now.Add(-time.Hour)})
+	roster.Add(players.Player{Number: 1, Name: "Late", Joined: now.Add(-time.Minute)})
+	roster.Add(players.Player{Number: 2, Name: "Member", Joined: now})
+	client := &fakeClient{}
+	return New(Cfg{
+		Limit: 3,
+		Members: []Member{
+			{GUID: "22222222222222222222222222222222"},
+			{GUID: "33333333333333333333333333333333"},
+		},
+		Message: "Sorry {name}, slot reserved for {member}",
+		DryRun:  dryRun,
+	}, client, roster), client
+}
+
+func Test_HandleGUIDVerified(t *testing.T) {
+	s, client := newSlots(false)
+	s.HandleGUIDVerified(events.GUIDVerified{Number: 3, Name: "NewMember", GUID: "33333333333333333333333333333333"})
+	expected := []string{"kick 1 Sorry Late, slot reserved for NewMember"}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_HandleGUIDVerifiedPublic(t *testing.T) {
+	s, client := newSlots(false)
+	s.HandleGUIDVerified(events.GUIDVerified{Number: 1, Name: "Late", GUID: "11111111111111111111111111111111"})
+	if len(client.commands) != 0 {
+		t.Error("Expected no kick for public player Got:", client.commands)
+	}
+}
+
+func Test_HandleGUIDVerifiedDryRun(t *testing.T) {
+	s, client := newSlots(true)
+	s.HandleGUIDVerified(events.GUIDVerified{Number: 3, Name: "NewMember", GUID: "33333333333333333333333333333333"})
+	if len(client.commands) != 0 {
+		t.Error("Expected no kick in dry-run Got:", client.commands)
+	}
+}
+
+func Test_victimMembers(t *testing.T) {
+	s, _ := newSlots(false)
+	list := players.Parse(listOutput)
+	v, ok := s.victim(list, "33333333333333333333333333333333")
+	if !ok || v.Name != "Late" {
+		t.Error("Expected most recently joined public player Got:", v)
+	}
+	if v, ok := s.victim(list[2:], "33333333333333333333333333333333"); ok {
+		t.Error("Expected members with higher priority never to be kicked Got:", v)
+	}
+}
+
+func Test_victimPriority(t *testing.T) {
+	s, _ := newSlots(false)
+	list := players.Parse(listOutput)
+	v, ok := s.victim(list[1:], "22222222222222222222222222222222")
+	if !ok || v.Name != "Late" {
+		t.Error("Expected public player before members Got:", v)
+	}
+	v, ok = s.victim(list[2:], "22222222222222222222222222222222")
+	if !ok || v.Name != "NewMember" {
+		t.Error("Expected member with lower priority Got:", v)
+	}
+	s.priority["44444444444444444444444444444444"] = math.MaxInt32
+	v, ok = s.victim(list[2:], "44444444444444444444444444444444")
+	if !ok || v.Name != "NewMember" {
+		t.Error("Expected member with the lowest priority Got:", v)
+	}
+}
@@ -0,0 +1,294 @@
+package vote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/chatcmd"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+var (
+	//ErrVoteRunning is returned when starting a vote while another one is running
+	ErrVoteRunning = errors.New("Another vote is already running")
+	//ErrNoVote is returned when voting without a running vote
+	ErrNoVote = errors.New("There is no vote running")
+	//ErrProtected is returned when starting a vote against an admin
+	ErrProtected = errors.New("This player can not be vote kicked")
+)
+
+//Cfg contains all data required by the Voting
+type Cfg struct {
+	Window     time.Duration
+	Quorum     int
+	Percent    int
+	Cooldown   time.Duration
+	Admins     []string
+	KickReason string
+}
+
+//Config is the Interface providing Configs for the Voting
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to announce votes and kick players
+type Client interface {
+	players.Querier
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Restarter restarts the server
+type Restarter interface {
+	Restart() error
+}
+
+type poll struct {
+	kind    string
+	target  players.Player
+	starter string
+	votes   map[string]bool
+	timer   *time.Timer
+}
+
+//Voting lets players kick other players or restart the server by majority vote
+type Voting struct {
+	client     Client
+	roster     *players.Roster
+	restarter  Restarter
+	window     time.Duration
+	quorum     int
+	percent    int
+	cooldown   time.Duration
+	admins     map[string]bool
+	kickReason string
+
+	sync.Mutex
+	current  *poll
+	lastVote time.Time
+}
+
+//New creates a Voting with given Config, restarter may be nil to restart via #restartserver
+func New(c Config, client Client, roster *players.Roster, restarter Restarter) *Voting {
+	cfg := c.GetConfig()
+	if cfg.Window == 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.Percent == 0 {
+		cfg.Percent = 60
+	}
+	if cfg.KickReason == "" {
+		cfg.KickReason = "Kicked by vote"
+	}
+	v := &Voting{
+		client:     client,
+		roster:     roster,
+		restarter:  restarter,
+		window:     cfg.Window,
+		quorum:     cfg.Quorum,
+		percent:    cfg.Percent,
+		cooldown:   cfg.Cooldown,
+		admins:     make(map[string]bool),
+		kickReason: cfg.KickReason,
+	}
+	for _, guid := range cfg.Admins {
+		v.admins[strings.ToLower(guid)] = true
+	}
+	return v
+}
+
+//Register the vote commands at the chat command router
+func (v *Voting) Register(r *chatcmd.Router) {
+	r.Register(chatcmd.Command{
+		Name:  "votekick",
+		Usage: "<name>",
+		Help:  "Starts a vote to kick a player",
+		Handler: func(ctx chatcmd.Context) (string, error) {
+			target, _, err := r.FindPlayer(ctx.Args)
+			if err != nil {
+				return "", err
+			}
+			return "", v.Start("kick", ctx.Player, target)
+		},
+	})
+	r.Register(chatcmd.Command{
+		Name: "voterestart",
+		Help: "Starts a vote to restart the server",
+		Handler: func(ctx chatcmd.Context) (string, error) {
+			return "", v.Start("restart", ctx.Player, players.Player{})
+		},
+	})
+	r.Register(chatcmd.Command{
+		Name: "yes",
+		Help: "Votes yes in the running vote",
+		Handler: func(ctx chatcmd.Context) (string, error) {
+			return v.Vote(ctx.Player, true)
+		},
+	})
+	r.Register(chatcmd.Command{
+		Name: "no",
+		Help: "Votes no in the running vote",
+		Handler: func(ctx chatcmd.Context) (string, error) {
+			return v.Vote(ctx.Player, false)
+		},
+	})
+}
+
+//Start a vote of the given kind (kick or restart)
+func (v *Voting) Start(kind string, starter, target players.Player) error {
+	v.Lock()
+	defer v.Unlock()
+	if v.current != nil {
+		return ErrVoteRunning
+	}
+	if wait := v.cooldown - time.Since(v.lastVote); wait > 0 {
+		return fmt.Errorf("Please wait %v before starting another vote", message.Duration(wait))
+	}
+	if kind == "kick" && target.GUID != "" && v.admins[target.GUID] {
+		return ErrProtected
+	}
+	p := &poll{
+		kind:    kind,
+		target:  target,
+		starter: starter.Name,
+		votes:   map[string]bool{starter.Name: true},
+	}
+	v.current = p
+	p.timer = time.AfterFunc(v.window, func() { v.finish(p) })
+	glog.Infof("Vote: %v started %v", starter.Name, p.describe())
+	v.announce(fmt.Sprintf("%v started a vote to %v. Type !yes or !no (%v left)",
+		starter.Name, p.describe(), message.Duration(v.window)))
+	return nil
+}
+
+//Vote for the running vote, every player may change his vote until it ends
+func (v *Voting) Vote(p players.Player, yes bool) (string, error) {
+	v.Lock()
+	current := v.current
+	if current == nil {
+		v.Unlock()
+		return "", ErrNoVote
+	}
+	current.votes[p.Name] = yes
+	count, _ := current.count()
+	v.Unlock()
+	if yes && count >= v.required(v.playerCount()) {
+		if current.timer.Stop() {
+			v.finish(current)
+		}
+		return "", nil
+	}
+	return "Your vote was counted", nil
+}
+
+//finish the poll and execute it if it passed
+func (v *Voting) finish(p *poll) {
+	count := v.playerCount()
+	v.Lock()
+	if v.current != p {
+		v.Unlock()
+		return
+	}
+	v.current = nil
+	v.lastVote = time.Now()
+	yes, no := p.count()
+	passed := yes >= v.required(count)
+	v.Unlock()
+
+	glog.Infof("Vote to %v ended with %v yes / %v no of %v players (passed: %v)", p.describe(), yes, no, count, passed)
+	if !passed {
+		v.announce(fmt.Sprintf("Vote to %v failed (%v yes, %v required)", p.describe(), yes, v.required(count)))
+		return
+	}
+	v.announce(fmt.Sprintf("Vote to %v passed (%v yes, %v no)", p.describe(), yes, no))
+	switch p.kind {
+	case "kick":
+		target, ok := v.resolve(p.target)
+		if !ok {
+			glog.Infof("Vote: %v left before the vote ended, nobody is kicked", p.target.Name)
+			v.announce(fmt.Sprintf("%v already left the server", p.target.Name))
+			return
+		}
+		v.client.RunCommand(fmt.Sprintf("kick %d %s", target.Number, v.kickReason), nil)
+	case "restart":
+		if v.restarter != nil {
+			if err := v.restarter.Restart(); err != nil {
+				glog.Errorln("Vote could not restart the Server:", err)
+			}
+		} else {
+			v.client.RunCommand("#restartserver", nil)
+		}
+	}
+}
+
+//required returns the amount of yes votes required for the given player count
+func (v *Voting) required(count int) int {
+	required := (count*v.percent + 99) / 100
+	if required < v.quorum {
+		required = v.quorum
+	}
+	if required < 1 {
+		required = 1
+	}
+	return required
+}
+
+//resolve finds target in the current player list so nobody else is kicked after the target left and its slot was reused
+func (v *Voting) resolve(target players.Player) (players.Player, bool) {
+	list, err := players.List(v.client)
+	if err != nil {
+		glog.Errorln("Vote could not retrieve Players:", err)
+		return players.Player{}, false
+	}
+	for _, p := range list {
+		if target.GUID != "" && p.GUID == target.GUID {
+			return p, true
+		}
+		if target.GUID == "" && p.GUID == "" && p.Number == target.Number && p.Name == target.Name {
+			return p, true
+		}
+	}
+	return players.Player{}, false
+}
+
+func (v *Voting) playerCount() int {
+	list, err := players.List(v.client)
+	if err != nil {
+		glog.V(2).Infoln("Vote could not retrieve Players:", err)
+		return v.roster.Count()
+	}
+	return len(list)
+}
+
+func (v *Voting) announce(text string) {
+	v.client.RunCommand("say -1 "+text, nil)
+}
+
+func (p *poll) count() (yes, no int) {
+	for _, vote := range p.votes {
+		if vote {
+			yes++
+		} else {
+			no++
+		}
+	}
+	return
+}
+
+func (p *poll) describe() string {
+	if p.kind == "kick" {
+		return "kick " + p.target.Name
+	}
+	return p.kind + " the server"
+}
@@ -0,0 +1,164 @@
+package vote
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/chatcmd"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	sync.Mutex
+	count    int
+	commands []string
+	//replaced are slots taken over by another player
+	replaced map[int]string
+}
+
+func (f *fakeClient) Query(cmd string, timeout time.Duration) (string, error) {
+	f.Lock()
+	defer f.Unlock()
+	list := ""
+	for i := 0; i < f.count; i++ {
+		name := fmt.Sprintf("Player%d", i)
+		if replacement, ok := f.replaced[i]; ok {
+			name = replacement
+		}
+		list += fmt.Sprintf("%d   127.0.0.1:2304   31   %v(OK) %v\n", i, guid(name), name)
+	}
+	return list, nil
+}
+
+func guid(name string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(name)))
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.Lock()
+	f.commands = append(f.commands, cmd)
+	f.Unlock()
+}
+
+type fakeRestarter struct {
+	restarted bool
+}
+
+func (f *fakeRestarter) Restart() error {
+	f.restarted = true
+	return nil
+}
+
+func newVoting(count int) (*Voting, *chatcmd.Router, *fakeClient, *fakeRestarter) {
+	roster := players.NewRoster()
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("Player%d", i)
+		roster.Add(players.Player{Number: i, Name: name, GUID: guid(name)})
+	}
+	roster.Add(players.Player{Number: 10, Name: "Admin", GUID: "0123456789abcdef0123456789abcdef"})
+	client := &fakeClient{count: count}
+	restarter := &fakeRestarter{}
+	v := New(Cfg{
+		Window:  time.Hour,
+		Quorum:  2,
+		Percent: 50,
+		Admins:  []string{"0123456789abcdef0123456789abcdef"},
+	}, client, roster, restarter)
+	r, _ := chatcmd.New(chatcmd.Cfg{}, client, roster)
+	v.Register(r)
+	return v, r, client, restarter
+}
+
+func chat(r *chatcmd.Router, name, text string) {
+	r.HandleChat(events.Chat{Channel: "Global", Name: name, Text: text, Message: name + ": " + text})
+}
+
+func Test_VoteKick(t *testing.T) {
+	_, r, client, _ := newVoting(4)
+	chat(r, "Player0", "!votekick player3")
+	chat(r, "Player1", "!yes")
+	expected := []string{
+		"say -1 Player0 started a vote to kick Player3. Type !yes or !no (1h left)",
+		"say -1 Vote to kick Player3 passed (2 yes, 0 no)",
+		"kick 3 Kicked by vote",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_VoteKickTargetLeft(t *testing.T) {
+	_, r, client, _ := newVoting(4)
+	chat(r, "Player0", "!votekick player3")
+	client.Lock()
+	client.replaced = map[int]string{3: "Newcomer"}
+	client.Unlock()
+	chat(r, "Player1", "!yes")
+	expected := []string{
+		"say -1 Player0 started a vote to kick Player3. Type !yes or !no (1h left)",
+		"say -1 Vote to kick Player3 passed (2 yes, 0 no)",
+		"say -1 Player3 already left the server",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_VoteRestartFails(t *testing.T) {
+	v, r, client, restarter := newVoting(6)
+	chat(r, "Player0", "!voterestart")
+	chat(r, "Player1", "!no")
+	chat(r, "Player2", "!yes")
+	v.finish(v.current)
+	if restarter.restarted {
+		t.Error("Expected no restart")
+	}
+	last := client.commands[len(client.commands)-1]
+	if last != "say -1 Vote to restart the server failed (2 yes, 3 required)" {
+		t.Error("Unexpected Announcement:", last)
+	}
+}
+
+func Test_VoteRestart(t *testing.T) {
+	_, r, _, restarter := newVoting(3)
+	chat(r, "Player0", "!voterestart")
+	chat(r, "Player1", "!yes")
+	if !restarter.restarted {
+		t.Error("Expected restart")
+	}
+}
+
+func Test_VoteProtection(t *testing.T) {
+	_, r, client, _ := newVoting(3)
+	chat(r, "Player0", "!votekick Admin")
+	chat(r, "Player1", "!votekick Player0")
+	chat(r, "Player2", "!voterestart")
+	expected := []string{
+		"say 0 " + ErrProtected.Error(),
+		"say -1 Player1 started a vote to kick Player0. Type !yes or !no (1h left)",
+		"say 2 " + ErrVoteRunning.Error(),
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_required(t *testing.T) {
+	v, _, _, _ := newVoting(0)
+	var tests = []struct {
+		count, required int
+	}{
+		{0, 2}, {3, 2}, {4, 2}, {5, 3}, {20, 10},
+	}
+	for _, test := range tests {
+		if res := v.required(test.count); res != test.required {
+			t.Errorf("%v Players Expected: %v Got: %v", test.count, test.required, res)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package warnings
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/audit"
+	"github.com/playnet-public/gorcon-arma/beguid"
+	"github.com/playnet-public/gorcon-arma/chatcmd"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Threshold sanctions players reaching the given amount of warnings
+type Threshold struct {
+	Warnings int
+	Action   string
+	Minutes  int
+	Message  string
+}
+
+//Cfg contains all data required by the Manager
+type Cfg struct {
+	Message    string
+	Thresholds []Threshold
+	Admins     []string
+}
+
+//Config is the Interface providing Configs for the Manager
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to message, kick and ban players
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Manager attaches warnings and notes to players and sanctions repeated warnings
+type Manager struct {
+	client     Client
+	roster     *players.Roster
+	db         *playerdb.DB
+	audit      *audit.Log
+	console    io.Writer
+	message    string
+	thresholds []Threshold
+	admins     map[string]bool
+}
+
+//New creates a Manager with given Config, notes of joining players are written to console if not nil
+func New(c Config, client Client, roster *players.Roster, db *playerdb.DB, auditLog *audit.Log, console io.Writer) (*Manager, error) {
+	cfg := c.GetConfig()
+	if cfg.Message == "" {
+		cfg.Message = "Warning {count}: {reason}"
+	}
+	for _, t := range cfg.Thresholds {
+		if t.Warnings <= 0 {
+			return nil, fmt.Errorf("warning threshold requires a positive amount of warnings, got %v", t.Warnings)
+		}
+		if t.Action != "kick" && t.Action != "ban" {
+			return nil, fmt.Errorf("unknown warning threshold action %q", t.Action)
+		}
+	}
+	m := &Manager{
+		client:     client,
+		roster:     roster,
+		db:         db,
+		audit:      auditLog,
+		console:    console,
+		message:    cfg.Message,
+		thresholds: append([]Threshold(nil), cfg.Thresholds...),
+		admins:     make(map[string]bool),
+	}
+	sort.Slice(m.thresholds, func(i, j int) bool { return m.thresholds[i].Warnings < m.thresholds[j].Warnings })
+	for _, guid := range cfg.Admins {
+		m.admins[strings.ToLower(guid)] = true
+	}
+	return m, nil
+}
+
+//ErrEmpty is returned for warnings and notes without text
+var ErrEmpty = errors.New("Text must not be empty")
+
+//Warn a player, online players are informed and sanctioned once they reach a threshold
+func (m *Manager) Warn(guid, author, reason string) (playerdb.Record, error) {
+	if strings.TrimSpace(reason) == "" {
+		return playerdb.Record{}, ErrEmpty
+	}
+	r, err := m.db.Warn(guid, author, reason)
+	if err != nil {
+		return r, err
+	}
+	glog.Infof("%v warned %v (%v): %v", author, r.Name, r.GUID, reason)
+	p, online := m.online(r.GUID)
+	m.audit.Record(audit.Entry{
+		Source: "warnings",
+		Action: "warn",
+		Name:   r.Name,
+		GUID:   r.GUID,
+		IP:     p.IP,
+		Reason: reason,
+	})
+	if online {
+		text := message.Render(m.message, message.Vars{
+			"name":   p.Name,
+			"count":  len(r.Warnings),
+			"reason": reason,
+		})
+		m.client.RunCommand(fmt.Sprintf("say %d %s", p.Number, text), nil)
+	}
+	return m.enforce(r, p, online)
+}
+
+//Note attaches a note to a player, shown to admins whenever the player joins
+func (m *Manager) Note(guid, author, text string) (playerdb.Record, error) {
+	if strings.TrimSpace(text) == "" {
+		return playerdb.Record{}, ErrEmpty
+	}
+	return m.db.Note(guid, author, text)
+}
+
+//HandleGUIDVerified shows the notes of joining players and applies sanctions of warnings given while offline
+func (m *Manager) HandleGUIDVerified(e events.GUIDVerified) {
+	r := m.db.Get(e.GUID)
+	p, _ := m.roster.ByNumber(e.Number)
+	p.Number, p.Name, p.GUID = e.Number, e.Name, r.GUID
+	if _, err := m.enforce(r, p, true); err != nil {
+		glog.Errorln("Could not record Sanction:", err)
+	}
+	if len(r.Notes) == 0 && len(r.Warnings) == 0 {
+		return
+	}
+	lines := []string{fmt.Sprintf("%s joined with %d warnings", e.Name, len(r.Warnings))}
+	for _, n := range r.Notes {
+		lines = append(lines, fmt.Sprintf("Note on %s: %s (%s)", e.Name, n.Text, n.Author))
+	}
+	for _, line := range lines {
+		if m.console != nil {
+			fmt.Fprintln(m.console, line)
+		}
+		for _, admin := range m.roster.All() {
+			if admin.Verified && m.admins[admin.GUID] && admin.Number != e.Number {
+				m.client.RunCommand(fmt.Sprintf("say %d %s", admin.Number, line), nil)
+			}
+		}
+	}
+}
+
+//enforce the highest threshold reached since the latest sanction
+//Kicks require the player to be online and stay pending until the next join
+func (m *Manager) enforce(r playerdb.Record, p players.Player, online bool) (playerdb.Record, error) {
+	var threshold *Threshold
+	for i, t := range m.thresholds {
+		if t.Warnings <= len(r.Warnings) && t.Warnings > r.Sanctioned {
+			threshold = &m.thresholds[i]
+		}
+	}
+	if threshold == nil || (threshold.Action == "kick" && !online) {
+		return r, nil
+	}
+	reason := message.Render(threshold.Message, message.Vars{
+		"name":    r.Name,
+		"count":   len(r.Warnings),
+		"minutes": threshold.Minutes,
+	})
+	if reason == "" {
+		reason = fmt.Sprintf("%d warnings", len(r.Warnings))
+	}
+	switch {
+	case threshold.Action == "kick":
+		m.client.RunCommand(fmt.Sprintf("kick %d %s", p.Number, reason), nil)
+	case online:
+		m.client.RunCommand(fmt.Sprintf("ban %d %d %s", p.Number, threshold.Minutes, reason), nil)
+	default:
+		m.client.RunCommand(fmt.Sprintf("addBan %s %d %s", r.GUID, threshold.Minutes, reason), nil)
+	}
+	glog.Infof("Sanctioning %v (%v) with %v after %d warnings", r.Name, r.GUID, threshold.Action, len(r.Warnings))
+	m.audit.Record(audit.Entry{
+		Source: "warnings",
+		Action: threshold.Action,
+		Name:   r.Name,
+		GUID:   r.GUID,
+		IP:     p.IP,
+		Reason: reason,
+	})
+	count := len(r.Warnings)
+	return m.db.Update(r.GUID, func(r *playerdb.Record) {
+		r.Sanctioned = count
+	})
+}
+
+//online returns the roster entry of guid
+func (m *Manager) online(guid string) (players.Player, bool) {
+	for _, p := range m.roster.All() {
+		if p.GUID == guid {
+			return p, true
+		}
+	}
+	return players.Player{}, false
+}
+
+//ErrNotVerified is returned for players whose GUID is not known yet
+var ErrNotVerified = errors.New("Player GUID not verified yet")
+
+//Register the warn, note and notes chat commands on r
+func (m *Manager) Register(r *chatcmd.Router) {
+	r.Register(chatcmd.Command{
+		Name:  "warn",
+		Usage: "<name|guid|steamid64> <reason>",
+		Help:  "Warns a player",
+		Admin: true,
+		Handler: func(ctx chatcmd.Context) (string, error) {
+			guid, rest, err := m.target(r, ctx.Args)
+			if err != nil {
+				return "", err
+			}
+			res, err := m.Warn(guid, ctx.Player.Name, strings.Join(rest, " "))
+			if err == ErrEmpty {
+				return "", chatcmd.ErrUsage
+			}
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Warned %s (%d warnings)", res.Name, len(res.Warnings)), nil
+		},
+	})
+	r.Register(chatcmd.Command{
+		Name:  "note",
+		Usage: "<name|guid|steamid64> <text>",
+		Help:  "Attaches a note to a player",
+		Admin: true,
+		Handler: func(ctx chatcmd.Context) (string, error) {
+			guid, rest, err := m.target(r, ctx.Args)
+			if err != nil {
+				return "", err
+			}
+			res, err := m.Note(guid, ctx.Player.Name, strings.Join(rest, " "))
+			if err == ErrEmpty {
+				return "", chatcmd.ErrUsage
+			}
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Noted on %s (%d notes)", res.Name, len(res.Notes)), nil
+		},
+	})
+	r.Register(chatcmd.Command{
+		Name:  "notes",
+		Usage: "<name|guid|steamid64>",
+		Help:  "Shows warnings and notes of a player",
+		Admin: true,
+		Handler: func(ctx chatcmd.Context) (string, error) {
+			guid, _, err := m.target(r, ctx.Args)
+			if err != nil {
+				return "", err
+			}
+			return Summary(m.db.Get(guid)), nil
+		},
+	})
+}
+
+//target resolves the player given as first argument by GUID, SteamID64 or name
+func (m *Manager) target(r *chatcmd.Router, args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, chatcmd.ErrUsage
+	}
+	if guid, _, err := beguid.Resolve(args[0]); err == nil {
+		return guid, args[1:], nil
+	}
+	p, rest, err := r.FindPlayer(args)
+	if err != nil {
+		return "", nil, err
+	}
+	if p.GUID == "" {
+		return "", nil, ErrNotVerified
+	}
+	return p.GUID, rest, nil
+}
+
+//Summary lists the warnings and notes of r in one line
+func Summary(r playerdb.Record) string {
+	parts := []string{fmt.Sprintf("%s: %d warnings", r.Name, len(r.Warnings))}
+	for _, w := range r.Warnings {
+		parts = append(parts, fmt.Sprintf("Warning %s: %s (%s)", w.Time.Format("2006-01-02"), w.Text, w.Author))
+	}
+	for _, n := range r.Notes {
+		parts = append(parts, fmt.Sprintf("Note %s: %s (%s)", n.Time.Format("2006-01-02"), n.Text, n.Author))
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,127 @@
+package warnings
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+const (
+	adminGUID  = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	playerGUID = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func newManager(t *testing.T) (*Manager, *fakeClient, *playerdb.DB, *players.Roster, *bytes.Buffer, func()) {
+	dir, err := ioutil.TempDir("", "warnings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := playerdb.Open(path.Join(dir, "players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roster := players.NewRoster()
+	roster.Add(players.Player{Number: 0, Name: "Admin", GUID: adminGUID, Verified: true})
+	client := &fakeClient{}
+	console := &bytes.Buffer{}
+	m, err := New(Cfg{
+		Thresholds: []Threshold{
+			{Warnings: 3, Action: "ban", Minutes: 60, Message: "{count} warnings"},
+			{Warnings: 2, Action: "kick", Message: "Kicked after {count} warnings"},
+		},
+		Admins: []string{adminGUID},
+	}, client, roster, db, nil, console)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, client, db, roster, console, func() { os.RemoveAll(dir) }
+}
+
+func Test_New(t *testing.T) {
+	if _, err := New(Cfg{Thresholds: []Threshold{{Warnings: 1, Action: "slap"}}}, nil, nil, nil, nil, nil); err == nil {
+		t.Error("Expected Error for unknown Action")
+	}
+}
+
+func Test_Warn(t *testing.T) {
+	m, client, db, roster, _, cleanup := newManager(t)
+	defer cleanup()
+	db.Visit(playerGUID, "Steve", "10.0.0.1")
+	roster.Add(players.Player{Number: 5, Name: "Steve", GUID: playerGUID})
+
+	m.Warn(playerGUID, "Admin", "Teamkilling")
+	m.Warn(playerGUID, "Admin", "Teamkilling again")
+	expected := []string{
+		"say 5 Warning 1: Teamkilling",
+		"say 5 Warning 2: Teamkilling again",
+		"kick 5 Kicked after 2 warnings",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+
+	roster.Remove(5)
+	client.commands = nil
+	r, err := m.Warn(playerGUID, "Admin", "Evading")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"addBan " + playerGUID + " 60 3 warnings"}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+	if r.Sanctioned != 3 || len(r.Warnings) != 3 {
+		t.Error("Unexpected Record:", r)
+	}
+	if _, err := m.Warn(playerGUID, "Admin", " "); err != ErrEmpty {
+		t.Error("Expected ErrEmpty Got:", err)
+	}
+}
+
+func Test_PendingKick(t *testing.T) {
+	m, client, db, roster, console, cleanup := newManager(t)
+	defer cleanup()
+	db.Visit(playerGUID, "Steve", "10.0.0.1")
+	m.Warn(playerGUID, "Admin", "Spamming")
+	m.Warn(playerGUID, "Admin", "Spamming")
+	m.Note(playerGUID, "Admin", "Watch the chat")
+	if len(client.commands) != 0 {
+		t.Fatal("Expected no Commands for offline Player Got:", client.commands)
+	}
+
+	roster.Add(players.Player{Number: 7, Name: "Steve", GUID: playerGUID})
+	m.HandleGUIDVerified(events.GUIDVerified{Number: 7, Name: "Steve", GUID: playerGUID})
+	expected := []string{
+		"kick 7 Kicked after 2 warnings",
+		"say 0 Steve joined with 2 warnings",
+		"say 0 Note on Steve: Watch the chat (Admin)",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+	if console.String() != "Steve joined with 2 warnings\nNote on Steve: Watch the chat (Admin)\n" {
+		t.Error("Unexpected Console Output:", console.String())
+	}
+
+	client.commands = nil
+	m.HandleGUIDVerified(events.GUIDVerified{Number: 7, Name: "Steve", GUID: playerGUID})
+	if len(client.commands) != 2 {
+		t.Error("Expected Sanction to be applied once Got:", client.commands)
+	}
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+//Timeout for delivering a message to one webhook
+var Timeout = time.Second * 10
+
+//Sender posts messages to a list of webhook URLs
+//The payload contains the text as content (Discord) and text (Slack, Mattermost)
+type Sender struct {
+	urls   []string
+	client *http.Client
+}
+
+//New creates a Sender for urls, a Sender without urls discards all messages
+func New(urls []string) *Sender {
+	return &Sender{
+		urls:   urls,
+		client: &http.Client{Timeout: Timeout},
+	}
+}
+
+//Send text to all webhooks in the background, a nil Sender discards all messages
+func (s *Sender) Send(text string) {
+	if s == nil {
+		return
+	}
+	for _, url := range s.urls {
+		go func(url string) {
+			if err := s.post(url, text); err != nil {
+				glog.Errorf("Could not send Webhook to %v: %v", url, err)
+			}
+		}(url)
+	}
+}
+
+func (s *Sender) post(url, text string) error {
+	body, err := json.Marshal(map[string]string{
+		"content": text,
+		"text":    text,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := s.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %v", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_Send(t *testing.T) {
+	received := make(chan map[string]string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		received <- payload
+	}))
+	defer server.Close()
+
+	New([]string{server.URL}).Send("Alert")
+	select {
+	case payload := <-received:
+		if payload["content"] != "Alert" || payload["text"] != "Alert" {
+			t.Error("Unexpected Payload:", payload)
+		}
+	case <-time.After(time.Second):
+		t.Error("Webhook not sent")
+	}
+	var nilSender *Sender
+	nilSender.Send("Alert")
+}
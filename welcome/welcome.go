@@ -0,0 +1,114 @@
+package welcome
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/message"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+//Cfg contains all data required by the Welcome
+type Cfg struct {
+	First     []string
+	Returning []string
+	Rules     string
+	Delay     time.Duration
+}
+
+//Config is the Interface providing Configs for the Welcome
+type Config interface {
+	GetConfig() Cfg
+}
+
+//GetConfig returns the Cfg Object
+func (c Cfg) GetConfig() Cfg {
+	return c
+}
+
+//Client is the RCon Connection used to send the messages
+type Client interface {
+	RunCommand(cmd string, w io.WriteCloser)
+}
+
+//Restarts provides the time of the next scheduled restart
+type Restarts interface {
+	NextRestart() time.Time
+}
+
+//Welcome greets players once their GUID got verified
+type Welcome struct {
+	client    Client
+	roster    *players.Roster
+	db        *playerdb.DB
+	restarts  Restarts
+	first     []string
+	returning []string
+	rules     string
+	delay     time.Duration
+}
+
+//New creates a Welcome with given Config, restarts may be nil if no scheduler is running
+func New(c Config, client Client, roster *players.Roster, db *playerdb.DB, restarts Restarts) *Welcome {
+	cfg := c.GetConfig()
+	return &Welcome{
+		client:    client,
+		roster:    roster,
+		db:        db,
+		restarts:  restarts,
+		first:     cfg.First,
+		returning: cfg.Returning,
+		rules:     cfg.Rules,
+		delay:     cfg.Delay,
+	}
+}
+
+//HandleGUIDVerified sends the welcome messages to the verified player
+func (w *Welcome) HandleGUIDVerified(e events.GUIDVerified) {
+	record := w.db.Get(e.GUID)
+	lines := w.returning
+	if record.Visits <= 1 {
+		lines = w.first
+	}
+	if len(lines) == 0 {
+		return
+	}
+	vars := message.Vars{
+		"name":    e.Name,
+		"visits":  record.Visits,
+		"restart": NextRestart(w.restarts),
+		"rules":   w.rules,
+		"players": w.roster.Count(),
+	}
+	send := func() {
+		//the player might have left while waiting
+		if p, ok := w.roster.ByNumber(e.Number); !ok || p.Name != e.Name {
+			return
+		}
+		glog.V(2).Infof("Welcoming %v (Visit %v)", e.Name, record.Visits)
+		for _, line := range lines {
+			w.client.RunCommand(fmt.Sprintf("say %d %s", e.Number, message.Render(line, vars)), nil)
+		}
+	}
+	if w.delay > 0 {
+		time.AfterFunc(w.delay, send)
+		return
+	}
+	send()
+}
+
+//NextRestart returns the time until the next restart in a human readable form
+func NextRestart(r Restarts) string {
+	if r == nil {
+		return "unknown"
+	}
+	next := r.NextRestart()
+	if next.IsZero() {
+		return "unknown"
+	}
+	return message.Duration(time.Until(next))
+}
@@ -0,0 +1,74 @@
+package welcome
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/playnet-public/gorcon-arma/events"
+	"github.com/playnet-public/gorcon-arma/playerdb"
+	"github.com/playnet-public/gorcon-arma/players"
+)
+
+type fakeClient struct {
+	commands []string
+}
+
+func (f *fakeClient) RunCommand(cmd string, w io.WriteCloser) {
+	f.commands = append(f.commands, cmd)
+}
+
+type fakeRestarts time.Time
+
+func (f fakeRestarts) NextRestart() time.Time {
+	return time.Time(f)
+}
+
+func Test_HandleGUIDVerified(t *testing.T) {
+	dir, err := ioutil.TempDir("", "welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := playerdb.Open(path.Join(dir, "players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roster := players.NewRoster()
+	roster.Add(players.Player{Number: 2, Name: "Steve"})
+	client := &fakeClient{}
+	w := New(Cfg{
+		First:     []string{"Welcome {name}!", "{rules}"},
+		Returning: []string{"Welcome back {name}, visit #{visits}. Restart in {restart}"},
+		Rules:     "No teamkilling",
+	}, client, roster, db, fakeRestarts(time.Now().Add(time.Hour*2+time.Second*30)))
+
+	guid := "0123456789abcdef0123456789abcdef"
+	e := events.GUIDVerified{Number: 2, Name: "Steve", GUID: guid}
+	db.Visit(guid, "Steve", "127.0.0.1")
+	w.HandleGUIDVerified(e)
+	db.Visit(guid, "Steve", "127.0.0.1")
+	w.HandleGUIDVerified(e)
+
+	expected := []string{
+		"say 2 Welcome Steve!",
+		"say 2 No teamkilling",
+		"say 2 Welcome back Steve, visit #2. Restart in 2h",
+	}
+	if !reflect.DeepEqual(client.commands, expected) {
+		t.Error("Expected:", expected, "Got:", client.commands)
+	}
+}
+
+func Test_NextRestart(t *testing.T) {
+	if res := NextRestart(nil); res != "unknown" {
+		t.Error("Expected: unknown Got:", res)
+	}
+	if res := NextRestart(fakeRestarts(time.Time{})); res != "unknown" {
+		t.Error("Expected: unknown Got:", res)
+	}
+}